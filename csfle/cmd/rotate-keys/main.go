@@ -0,0 +1,90 @@
+// Command rotate-keys rotates CSFLE data encryption keys and/or their KMS
+// wrapping master key for the employee_salary demo collection, recording
+// every run except dry runs to encryption.__rotationLog.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"csfle/internal/config"
+	"csfle/internal/keyvault"
+	"csfle/internal/kms"
+)
+
+func main() {
+	mode := flag.String("mode", "dek", `rotation mode: "dek" (create a new DEK and re-encrypt salaries under it) or "master-key" (rewrap existing DEKs under a new KMS master key)`)
+	oldKeyAltName := flag.String("old-key-alt-name", "go_encryption_example", "keyAltName of the DEK being rotated away from (dek mode)")
+	newKeyAltName := flag.String("new-key-alt-name", "", "keyAltName for the new DEK (required in dek mode)")
+	operator := flag.String("operator", "", "identifier of the person/system running this rotation, recorded in the audit log")
+	batchSize := flag.Int("batch-size", 100, "number of documents processed between resumable checkpoints (dek mode)")
+	dryRun := flag.Bool("dry-run", false, "report what would change without writing any data")
+	flag.Parse()
+
+	if *operator == "" {
+		log.Fatal("-operator is required")
+	}
+	if *mode == "dek" && *newKeyAltName == "" {
+		log.Fatal("-new-key-alt-name is required in dek mode")
+	}
+
+	cfg := config.Load()
+	provider, err := kms.Load(cfg)
+	if err != nil {
+		log.Fatalf("Error loading KMS provider: %v", err)
+	}
+
+	ctx := context.Background()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDB.URI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	keyVaultDB := client.Database(cfg.MongoDB.KeyVaultDatabase)
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(kms.BuildKMSProviders(provider)).
+		SetKeyVaultNamespace(cfg.MongoDB.KeyVaultNamespace())
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		log.Fatalf("Error configuring ClientEncryption: %v", err)
+	}
+	defer clientEncryption.Close(ctx)
+
+	rotator := keyvault.NewRotator(
+		clientEncryption,
+		keyVaultDB.Collection(cfg.MongoDB.KeyVaultCollection),
+		client.Database(cfg.MongoDB.Database).Collection(cfg.MongoDB.Collection),
+		keyVaultDB.Collection("__rotationLog"),
+		keyVaultDB.Collection("__rotationCheckpoints"),
+	)
+
+	switch *mode {
+	case "dek":
+		err = rotator.RotateDEK(ctx, keyvault.RotateDEKOptions{
+			Provider:      provider,
+			OldKeyAltName: *oldKeyAltName,
+			NewKeyAltName: *newKeyAltName,
+			Algorithm:     "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic",
+			Operator:      *operator,
+			BatchSize:     *batchSize,
+			DryRun:        *dryRun,
+		})
+	case "master-key":
+		_, err = rotator.RewrapMasterKey(ctx, bson.M{}, provider, *operator, *dryRun)
+	default:
+		log.Fatalf("Unknown -mode %q: use dek or master-key", *mode)
+	}
+
+	if err != nil {
+		log.Fatalf("Rotation failed: %v", err)
+	}
+
+	log.Println("Rotation complete.")
+}
@@ -0,0 +1,202 @@
+// Package config handles application configuration loading from environment variables.
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Config holds all application configuration.
+type Config struct {
+	MongoDB  MongoDBConfig
+	KMS      KMSConfig
+	KeyCache KeyCacheConfig
+
+	// EncryptionMode picks which encryption demo main() runs: "deterministic"
+	// (default) for equality-only CSFLE, or "queryable" for Queryable
+	// Encryption with server-side range queries on salary.
+	EncryptionMode string
+
+	// CryptSharedLibPath points at the mongo_crypt shared library used for
+	// automatic encryption/decryption in queryable mode. If empty, the driver
+	// falls back to spawning mongocryptd.
+	CryptSharedLibPath string
+}
+
+// KeyCacheConfig configures the internal/keycache DEK cache wrapping
+// ClientEncryption.
+type KeyCacheConfig struct {
+	TTL time.Duration
+}
+
+// MongoDBConfig holds MongoDB connection configuration.
+type MongoDBConfig struct {
+	URI                string
+	Database           string
+	Collection         string
+	KeyVaultDatabase   string
+	KeyVaultCollection string
+}
+
+// KeyVaultNamespace returns the "database.collection" namespace CSFLE uses
+// to store data encryption keys.
+func (c MongoDBConfig) KeyVaultNamespace() string {
+	return c.KeyVaultDatabase + "." + c.KeyVaultCollection
+}
+
+// KMSConfig selects and configures a KMS provider. Provider picks which of
+// the AWS/GCP/Azure/KMIP/Vault/Local sub-configs is actually used; the rest
+// are ignored.
+type KMSConfig struct {
+	Provider string // local, aws, gcp, azure, kmip, or vault
+
+	Local LocalKMSConfig
+	AWS   AWSKMSConfig
+	GCP   GCPKMSConfig
+	Azure AzureKMSConfig
+	KMIP  KMIPKMSConfig
+	Vault VaultKMSConfig
+}
+
+// LocalKMSConfig holds a base64-encoded 96-byte local master key.
+type LocalKMSConfig struct {
+	MasterKey string
+}
+
+// AWSKMSConfig holds AWS KMS credentials and the CMK used to wrap data keys.
+type AWSKMSConfig struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Key             string // CMK ARN or key ID
+}
+
+// GCPKMSConfig holds GCP Cloud KMS credentials and the key identifying fields.
+type GCPKMSConfig struct {
+	Email      string
+	PrivateKey string
+	ProjectID  string
+	Location   string
+	KeyRing    string
+	KeyName    string
+}
+
+// AzureKMSConfig holds Azure Key Vault credentials and the key identifying fields.
+type AzureKMSConfig struct {
+	TenantID         string
+	ClientID         string
+	ClientSecret     string
+	KeyVaultEndpoint string
+	KeyName          string
+	KeyVersion       string
+}
+
+// KMIPKMSConfig holds the endpoint of a KMIP-compliant key management server.
+type KMIPKMSConfig struct {
+	Endpoint string
+	KeyID    string // optional: reuse an existing KMIP key instead of creating one
+}
+
+// VaultKMSConfig holds the HashiCorp Vault connection and the path of the KV
+// secret holding the local master key. Vault isn't a KMS provider the driver
+// knows natively; it's a way to fetch the "local" provider's wrapping key
+// from a secrets manager instead of an env var.
+type VaultKMSConfig struct {
+	Addr     string
+	Token    string
+	KeyPath  string // e.g. "secret/data/csfle/master-key"
+	KeyField string // field within the secret holding the base64 key, default "key"
+}
+
+// Load loads configuration from a .env file and the process environment.
+func Load() *Config {
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found, using system environment variables")
+	}
+
+	return &Config{
+		EncryptionMode:     getEnv("ENCRYPTION_MODE", "deterministic"),
+		CryptSharedLibPath: getEnv("CRYPT_SHARED_LIB_PATH", ""),
+		KeyCache: KeyCacheConfig{
+			TTL: getEnvAsDuration("KEY_CACHE_TTL", 60*time.Second),
+		},
+		MongoDB: MongoDBConfig{
+			URI:                getEnv("MONGODB_URI", ""),
+			Database:           getEnv("MONGODB_DATABASE", "employee_data"),
+			Collection:         getEnv("MONGODB_COLLECTION", "employee_salary"),
+			KeyVaultDatabase:   getEnv("KEY_VAULT_DATABASE", "encryption"),
+			KeyVaultCollection: getEnv("KEY_VAULT_COLLECTION", "__keyVault"),
+		},
+		KMS: KMSConfig{
+			Provider: getEnv("KMS_PROVIDER", "local"),
+			Local: LocalKMSConfig{
+				MasterKey: getEnv("KMS_LOCAL_MASTER_KEY", ""),
+			},
+			AWS: AWSKMSConfig{
+				AccessKeyID:     getEnv("KMS_AWS_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("KMS_AWS_SECRET_ACCESS_KEY", ""),
+				SessionToken:    getEnv("KMS_AWS_SESSION_TOKEN", ""),
+				Region:          getEnv("KMS_AWS_REGION", ""),
+				Key:             getEnv("KMS_AWS_KEY", ""),
+			},
+			GCP: GCPKMSConfig{
+				Email:      getEnv("KMS_GCP_EMAIL", ""),
+				PrivateKey: getEnv("KMS_GCP_PRIVATE_KEY", ""),
+				ProjectID:  getEnv("KMS_GCP_PROJECT_ID", ""),
+				Location:   getEnv("KMS_GCP_LOCATION", ""),
+				KeyRing:    getEnv("KMS_GCP_KEY_RING", ""),
+				KeyName:    getEnv("KMS_GCP_KEY_NAME", ""),
+			},
+			Azure: AzureKMSConfig{
+				TenantID:         getEnv("KMS_AZURE_TENANT_ID", ""),
+				ClientID:         getEnv("KMS_AZURE_CLIENT_ID", ""),
+				ClientSecret:     getEnv("KMS_AZURE_CLIENT_SECRET", ""),
+				KeyVaultEndpoint: getEnv("KMS_AZURE_KEY_VAULT_ENDPOINT", ""),
+				KeyName:          getEnv("KMS_AZURE_KEY_NAME", ""),
+				KeyVersion:       getEnv("KMS_AZURE_KEY_VERSION", ""),
+			},
+			KMIP: KMIPKMSConfig{
+				Endpoint: getEnv("KMS_KMIP_ENDPOINT", ""),
+				KeyID:    getEnv("KMS_KMIP_KEY_ID", ""),
+			},
+			Vault: VaultKMSConfig{
+				Addr:     getEnv("KMS_VAULT_ADDR", ""),
+				Token:    getEnv("KMS_VAULT_TOKEN", ""),
+				KeyPath:  getEnv("KMS_VAULT_KEY_PATH", ""),
+				KeyField: getEnv("KMS_VAULT_KEY_FIELD", "key"),
+			},
+		},
+	}
+}
+
+// getEnv gets an environment variable or returns a default value
+func getEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvAsDuration gets an environment variable, interpreted as a number of
+// seconds, and returns it as a time.Duration, or defaultValue if unset or
+// invalid.
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	seconds, err := strconv.Atoi(valueStr)
+	if err != nil {
+		log.Printf("Warning: Error converting %s to int, using default value %s", key, defaultValue)
+		return defaultValue
+	}
+
+	return time.Duration(seconds) * time.Second
+}
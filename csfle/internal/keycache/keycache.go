@@ -0,0 +1,169 @@
+// Package keycache wraps *mongo.ClientEncryption with an in-memory,
+// TTL-expiring cache of keyAltName -> data key ID, plus a background
+// goroutine that proactively refreshes entries approaching expiry. This
+// avoids a key-vault round trip on every Encrypt call for a keyAltName
+// that's already been resolved recently.
+package keycache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// entry holds a resolved data key ID and when it was cached.
+type entry struct {
+	keyID    primitive.Binary
+	cachedAt time.Time
+}
+
+// Metrics is a point-in-time snapshot of cache counters.
+type Metrics struct {
+	CacheHit     int64
+	CacheMiss    int64
+	RefreshError int64
+}
+
+// Cache wraps a *mongo.ClientEncryption with a TTL cache of keyAltName ->
+// data key ID lookups, refreshed in the background so Encrypt rarely blocks
+// on a key-vault round trip.
+type Cache struct {
+	clientEncryption *mongo.ClientEncryption
+	keyVaultColl     *mongo.Collection
+	ttl              time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]entry
+
+	cacheHit     atomic.Int64
+	cacheMiss    atomic.Int64
+	refreshError atomic.Int64
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewCache builds a Cache around clientEncryption and starts its background
+// refresh goroutine. keyVaultColl is the same collection passed to
+// ensureDataKey, used to look up keyAltName -> key ID on a cache miss. Call
+// StopGC when the cache is no longer needed.
+func NewCache(clientEncryption *mongo.ClientEncryption, keyVaultColl *mongo.Collection, ttl time.Duration) *Cache {
+	c := &Cache{
+		clientEncryption: clientEncryption,
+		keyVaultColl:     keyVaultColl,
+		ttl:              ttl,
+		entries:          make(map[string]entry),
+		stopCh:           make(chan struct{}),
+		doneCh:           make(chan struct{}),
+	}
+
+	go c.refreshLoop()
+
+	return c
+}
+
+// StopGC shuts down the background refresh goroutine. Safe to call once.
+func (c *Cache) StopGC() {
+	close(c.stopCh)
+	<-c.doneCh
+}
+
+// Metrics returns a snapshot of the cache's hit/miss/refresh-error counters.
+func (c *Cache) Metrics() Metrics {
+	return Metrics{
+		CacheHit:     c.cacheHit.Load(),
+		CacheMiss:    c.cacheMiss.Load(),
+		RefreshError: c.refreshError.Load(),
+	}
+}
+
+// Encrypt resolves keyAltName's data key ID (via cache, or the key vault on a
+// miss) and encrypts val with it using algorithm.
+func (c *Cache) Encrypt(ctx context.Context, keyAltName string, val bson.RawValue, algorithm string) (primitive.Binary, error) {
+	keyID, err := c.resolveKeyID(ctx, keyAltName)
+	if err != nil {
+		return primitive.Binary{}, err
+	}
+
+	encryptionOpts := options.Encrypt().SetAlgorithm(algorithm).SetKeyID(keyID)
+	return c.clientEncryption.Encrypt(ctx, val, encryptionOpts)
+}
+
+// Decrypt decrypts val. There's no keyAltName to cache against on the decrypt
+// path, so this always goes straight to ClientEncryption; the cache only
+// saves round trips on the encrypt (key-lookup) path.
+func (c *Cache) Decrypt(ctx context.Context, val primitive.Binary) (bson.RawValue, error) {
+	return c.clientEncryption.Decrypt(ctx, val)
+}
+
+// resolveKeyID returns keyAltName's cached data key ID if it's present and
+// unexpired, otherwise fetches it from the key vault and caches the result.
+func (c *Cache) resolveKeyID(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	c.mu.RLock()
+	e, ok := c.entries[keyAltName]
+	c.mu.RUnlock()
+
+	if ok && time.Since(e.cachedAt) < c.ttl {
+		c.cacheHit.Add(1)
+		return e.keyID, nil
+	}
+
+	c.cacheMiss.Add(1)
+	return c.fetchAndCache(ctx, keyAltName)
+}
+
+func (c *Cache) fetchAndCache(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	var doc bson.M
+	if err := c.keyVaultColl.FindOne(ctx, bson.M{"keyAltNames": keyAltName}).Decode(&doc); err != nil {
+		return primitive.Binary{}, err
+	}
+
+	keyID := doc["_id"].(primitive.Binary)
+
+	c.mu.Lock()
+	c.entries[keyAltName] = entry{keyID: keyID, cachedAt: time.Now()}
+	c.mu.Unlock()
+
+	return keyID, nil
+}
+
+// refreshLoop periodically refetches entries approaching expiry so resolved
+// lookups stay warm instead of all expiring and missing at once.
+func (c *Cache) refreshLoop() {
+	defer close(c.doneCh)
+
+	ticker := time.NewTicker(c.ttl / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.refreshStale()
+		}
+	}
+}
+
+func (c *Cache) refreshStale() {
+	c.mu.RLock()
+	staleNames := make([]string, 0, len(c.entries))
+	for keyAltName, e := range c.entries {
+		if time.Since(e.cachedAt) >= c.ttl/2 {
+			staleNames = append(staleNames, keyAltName)
+		}
+	}
+	c.mu.RUnlock()
+
+	for _, keyAltName := range staleNames {
+		if _, err := c.fetchAndCache(context.Background(), keyAltName); err != nil {
+			c.refreshError.Add(1)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Package keyvault implements data-encryption-key and KMS master-key
+// rotation for the CSFLE demo, on top of the same ClientEncryption and key
+// vault used by main.go's deterministic-encryption flow.
+package keyvault
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"csfle/internal/kms"
+)
+
+// Rotator rotates data encryption keys (DEKs) and their wrapping KMS master
+// keys, recording every run except dry runs to an append-only rotation log
+// collection; a dry run only logs its document count.
+type Rotator struct {
+	clientEncryption *mongo.ClientEncryption
+	keyVaultColl     *mongo.Collection
+	coll             *mongo.Collection
+	rotationLogColl  *mongo.Collection
+	checkpointColl   *mongo.Collection
+}
+
+// NewRotator builds a Rotator. coll is the collection whose "salary" field
+// gets re-encrypted during a DEK rotation; rotationLogColl and
+// checkpointColl are typically encryption.__rotationLog and
+// encryption.__rotationCheckpoints.
+func NewRotator(clientEncryption *mongo.ClientEncryption, keyVaultColl, coll, rotationLogColl, checkpointColl *mongo.Collection) *Rotator {
+	return &Rotator{
+		clientEncryption: clientEncryption,
+		keyVaultColl:     keyVaultColl,
+		coll:             coll,
+		rotationLogColl:  rotationLogColl,
+		checkpointColl:   checkpointColl,
+	}
+}
+
+// RotationLog is an append-only audit record of one rotation run.
+type RotationLog struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty"`
+	Kind        string             `bson:"kind"` // "master-key-rewrap" or "dek-rotation"
+	OldKeyID    primitive.Binary   `bson:"oldKeyId,omitempty"`
+	NewKeyID    primitive.Binary   `bson:"newKeyId,omitempty"`
+	DocsTouched int64              `bson:"docsTouched"`
+	Operator    string             `bson:"operator"`
+	DryRun      bool               `bson:"dryRun"`
+	StartedAt   time.Time          `bson:"startedAt"`
+	FinishedAt  time.Time          `bson:"finishedAt,omitempty"`
+}
+
+// RewrapMasterKey re-encrypts every DEK matching filter with a new KMS
+// master key via ClientEncryption.RewrapManyDataKey. This rotates the
+// wrapping key only; it never touches an already-encrypted document field,
+// since those stay encrypted under the same (unchanged) DEK.
+func (r *Rotator) RewrapMasterKey(ctx context.Context, filter bson.M, provider kms.Provider, operator string, dryRun bool) (*mongo.RewrapManyDataKeyResult, error) {
+	logEntry := RotationLog{
+		Kind:      "master-key-rewrap",
+		Operator:  operator,
+		DryRun:    dryRun,
+		StartedAt: time.Now(),
+	}
+
+	if dryRun {
+		count, err := r.keyVaultColl.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		logEntry.DocsTouched = count
+		logEntry.FinishedAt = time.Now()
+		return nil, r.writeLog(ctx, logEntry, dryRun)
+	}
+
+	rewrapOpts := options.RewrapManyDataKey().SetProvider(provider.Name())
+	if masterKey := provider.MasterKey(); masterKey != nil {
+		rewrapOpts.SetMasterKey(masterKey)
+	}
+
+	result, err := r.clientEncryption.RewrapManyDataKey(ctx, filter, rewrapOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.BulkWriteResult != nil {
+		logEntry.DocsTouched = result.BulkWriteResult.ModifiedCount
+	}
+	logEntry.FinishedAt = time.Now()
+
+	if err := r.writeLog(ctx, logEntry, dryRun); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// RotateDEKOptions configures a DEK rotation run.
+type RotateDEKOptions struct {
+	Provider      kms.Provider
+	OldKeyAltName string
+	NewKeyAltName string
+	Algorithm     string // e.g. AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic
+	Operator      string
+	BatchSize     int // documents processed between checkpoints; defaults to 100
+	DryRun        bool
+}
+
+// checkpoint tracks an in-progress DEK rotation so it can resume after the
+// last document it successfully re-encrypted, instead of restarting.
+type checkpoint struct {
+	KeyAltName string             `bson:"_id"`
+	LastID     primitive.ObjectID `bson:"lastId"`
+	NewKeyID   primitive.Binary   `bson:"newKeyId"`
+}
+
+// RotateDEK creates a new DEK under opts.NewKeyAltName (or resumes an
+// in-progress one from a checkpoint), then walks coll re-encrypting every
+// document's "salary" field from the old key to the new one. Progress is
+// checkpointed by _id every opts.BatchSize documents, so an interrupted run
+// picks up where it left off rather than starting over. opts.DryRun counts
+// the documents a real run would touch without creating a key, writing a
+// checkpoint, or modifying coll.
+func (r *Rotator) RotateDEK(ctx context.Context, opts RotateDEKOptions) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+
+	cp, err := r.loadCheckpoint(ctx, opts.NewKeyAltName)
+	if err != nil {
+		return err
+	}
+
+	newKeyID, err := r.ensureNewKey(ctx, opts, cp)
+	if err != nil {
+		return err
+	}
+
+	oldKeyID, err := r.lookupKeyID(ctx, opts.OldKeyAltName)
+	if err != nil {
+		return err
+	}
+
+	logEntry := RotationLog{
+		Kind:      "dek-rotation",
+		OldKeyID:  oldKeyID,
+		NewKeyID:  newKeyID,
+		Operator:  opts.Operator,
+		DryRun:    opts.DryRun,
+		StartedAt: time.Now(),
+	}
+
+	filter := bson.M{}
+	if cp != nil && !cp.LastID.IsZero() {
+		filter["_id"] = bson.M{"$gt": cp.LastID}
+	}
+
+	cursor, err := r.coll.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var touched int64
+	var lastID primitive.ObjectID
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		id := doc["_id"].(primitive.ObjectID)
+		lastID = id
+
+		oldSalary, ok := doc["salary"].(primitive.Binary)
+		if !ok {
+			continue
+		}
+
+		if !opts.DryRun {
+			decrypted, err := r.clientEncryption.Decrypt(ctx, oldSalary)
+			if err != nil {
+				return err
+			}
+
+			newSalary, err := r.clientEncryption.Encrypt(ctx, decrypted, options.Encrypt().SetAlgorithm(opts.Algorithm).SetKeyID(newKeyID))
+			if err != nil {
+				return err
+			}
+
+			if _, err := r.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"salary": newSalary}}); err != nil {
+				return err
+			}
+		}
+
+		touched++
+		if !opts.DryRun && touched%int64(opts.BatchSize) == 0 {
+			if err := r.saveCheckpoint(ctx, opts.NewKeyAltName, lastID, newKeyID); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if !opts.DryRun && !lastID.IsZero() {
+		if err := r.saveCheckpoint(ctx, opts.NewKeyAltName, lastID, newKeyID); err != nil {
+			return err
+		}
+	}
+
+	logEntry.DocsTouched = touched
+	logEntry.FinishedAt = time.Now()
+	if err := r.writeLog(ctx, logEntry, opts.DryRun); err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	return r.clearCheckpoint(ctx, opts.NewKeyAltName)
+}
+
+// ensureNewKey returns the rotation's target DEK, creating it the first time
+// this keyAltName is rotated to and persisting it to the checkpoint so a
+// resumed run reuses the same DEK instead of creating a second one. On
+// opts.DryRun it never creates a key or writes a checkpoint: if the key
+// already exists it's returned read-only, and if it doesn't, a zero Binary
+// is returned since simulating the rotation doesn't require one.
+func (r *Rotator) ensureNewKey(ctx context.Context, opts RotateDEKOptions, cp *checkpoint) (primitive.Binary, error) {
+	if cp != nil {
+		return cp.NewKeyID, nil
+	}
+
+	newKeyID, err := r.lookupKeyID(ctx, opts.NewKeyAltName)
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			return primitive.Binary{}, err
+		}
+		if opts.DryRun {
+			return primitive.Binary{}, nil
+		}
+
+		dataKeyOpts := options.DataKey().SetKeyAltNames([]string{opts.NewKeyAltName})
+		if masterKey := opts.Provider.MasterKey(); masterKey != nil {
+			dataKeyOpts.SetMasterKey(masterKey)
+		}
+
+		newKeyID, err = r.clientEncryption.CreateDataKey(ctx, opts.Provider.Name(), dataKeyOpts)
+		if err != nil {
+			return primitive.Binary{}, err
+		}
+	}
+
+	if opts.DryRun {
+		return newKeyID, nil
+	}
+
+	_, err = r.checkpointColl.UpdateOne(ctx,
+		bson.M{"_id": opts.NewKeyAltName},
+		bson.M{"$set": bson.M{"newKeyId": newKeyID}},
+		options.Update().SetUpsert(true),
+	)
+	return newKeyID, err
+}
+
+func (r *Rotator) lookupKeyID(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	var doc bson.M
+	if err := r.keyVaultColl.FindOne(ctx, bson.M{"keyAltNames": keyAltName}).Decode(&doc); err != nil {
+		return primitive.Binary{}, err
+	}
+	return doc["_id"].(primitive.Binary), nil
+}
+
+func (r *Rotator) loadCheckpoint(ctx context.Context, newKeyAltName string) (*checkpoint, error) {
+	var cp checkpoint
+	err := r.checkpointColl.FindOne(ctx, bson.M{"_id": newKeyAltName}).Decode(&cp)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &cp, nil
+}
+
+func (r *Rotator) saveCheckpoint(ctx context.Context, newKeyAltName string, lastID primitive.ObjectID, newKeyID primitive.Binary) error {
+	_, err := r.checkpointColl.UpdateOne(ctx,
+		bson.M{"_id": newKeyAltName},
+		bson.M{"$set": bson.M{"lastId": lastID, "newKeyId": newKeyID}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *Rotator) clearCheckpoint(ctx context.Context, newKeyAltName string) error {
+	_, err := r.checkpointColl.DeleteOne(ctx, bson.M{"_id": newKeyAltName})
+	return err
+}
+
+func (r *Rotator) writeLog(ctx context.Context, entry RotationLog, dryRun bool) error {
+	if dryRun {
+		log.Printf("Dry run (%s): %d documents would be touched", entry.Kind, entry.DocsTouched)
+		return nil
+	}
+	_, err := r.rotationLogColl.InsertOne(ctx, entry)
+	return err
+}
@@ -0,0 +1,36 @@
+package kms
+
+import "csfle/internal/config"
+
+// AWSProvider wraps data keys with an AWS KMS customer master key (CMK).
+type AWSProvider struct {
+	cfg config.AWSKMSConfig
+}
+
+// NewAWSProvider builds an AWSProvider from cfg.
+func NewAWSProvider(cfg config.AWSKMSConfig) *AWSProvider {
+	return &AWSProvider{cfg: cfg}
+}
+
+// Name implements Provider.
+func (p *AWSProvider) Name() string { return "aws" }
+
+// Credentials implements Provider.
+func (p *AWSProvider) Credentials() map[string]interface{} {
+	creds := map[string]interface{}{
+		"accessKeyId":     p.cfg.AccessKeyID,
+		"secretAccessKey": p.cfg.SecretAccessKey,
+	}
+	if p.cfg.SessionToken != "" {
+		creds["sessionToken"] = p.cfg.SessionToken
+	}
+	return creds
+}
+
+// MasterKey implements Provider, identifying the CMK used to wrap each data key.
+func (p *AWSProvider) MasterKey() interface{} {
+	return map[string]interface{}{
+		"region": p.cfg.Region,
+		"key":    p.cfg.Key,
+	}
+}
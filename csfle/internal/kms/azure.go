@@ -0,0 +1,38 @@
+package kms
+
+import "csfle/internal/config"
+
+// AzureProvider wraps data keys with an Azure Key Vault key.
+type AzureProvider struct {
+	cfg config.AzureKMSConfig
+}
+
+// NewAzureProvider builds an AzureProvider from cfg.
+func NewAzureProvider(cfg config.AzureKMSConfig) *AzureProvider {
+	return &AzureProvider{cfg: cfg}
+}
+
+// Name implements Provider.
+func (p *AzureProvider) Name() string { return "azure" }
+
+// Credentials implements Provider.
+func (p *AzureProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{
+		"tenantId":     p.cfg.TenantID,
+		"clientId":     p.cfg.ClientID,
+		"clientSecret": p.cfg.ClientSecret,
+	}
+}
+
+// MasterKey implements Provider, identifying the Key Vault key used to wrap
+// each data key.
+func (p *AzureProvider) MasterKey() interface{} {
+	masterKey := map[string]interface{}{
+		"keyVaultEndpoint": p.cfg.KeyVaultEndpoint,
+		"keyName":          p.cfg.KeyName,
+	}
+	if p.cfg.KeyVersion != "" {
+		masterKey["keyVersion"] = p.cfg.KeyVersion
+	}
+	return masterKey
+}
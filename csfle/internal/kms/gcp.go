@@ -0,0 +1,35 @@
+package kms
+
+import "csfle/internal/config"
+
+// GCPProvider wraps data keys with a Google Cloud KMS key.
+type GCPProvider struct {
+	cfg config.GCPKMSConfig
+}
+
+// NewGCPProvider builds a GCPProvider from cfg.
+func NewGCPProvider(cfg config.GCPKMSConfig) *GCPProvider {
+	return &GCPProvider{cfg: cfg}
+}
+
+// Name implements Provider.
+func (p *GCPProvider) Name() string { return "gcp" }
+
+// Credentials implements Provider.
+func (p *GCPProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{
+		"email":      p.cfg.Email,
+		"privateKey": p.cfg.PrivateKey,
+	}
+}
+
+// MasterKey implements Provider, identifying the Cloud KMS key used to wrap
+// each data key.
+func (p *GCPProvider) MasterKey() interface{} {
+	return map[string]interface{}{
+		"projectId": p.cfg.ProjectID,
+		"location":  p.cfg.Location,
+		"keyRing":   p.cfg.KeyRing,
+		"keyName":   p.cfg.KeyName,
+	}
+}
@@ -0,0 +1,31 @@
+package kms
+
+import "csfle/internal/config"
+
+// KMIPProvider wraps data keys using a KMIP-compliant key management server.
+type KMIPProvider struct {
+	cfg config.KMIPKMSConfig
+}
+
+// NewKMIPProvider builds a KMIPProvider from cfg.
+func NewKMIPProvider(cfg config.KMIPKMSConfig) *KMIPProvider {
+	return &KMIPProvider{cfg: cfg}
+}
+
+// Name implements Provider.
+func (p *KMIPProvider) Name() string { return "kmip" }
+
+// Credentials implements Provider.
+func (p *KMIPProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{"endpoint": p.cfg.Endpoint}
+}
+
+// MasterKey implements Provider. KeyID is optional: when set, CreateDataKey
+// wraps the new data key with an existing KMIP key instead of asking the
+// server to generate one.
+func (p *KMIPProvider) MasterKey() interface{} {
+	if p.cfg.KeyID == "" {
+		return nil
+	}
+	return map[string]interface{}{"keyId": p.cfg.KeyID}
+}
@@ -0,0 +1,57 @@
+// Package kms resolves a configured master-key provider for Client-Side
+// Field Level Encryption: the credentials ClientEncryption registers per
+// provider, and (where applicable) the provider-specific MasterKey document
+// passed to CreateDataKey.
+package kms
+
+import (
+	"fmt"
+
+	"csfle/internal/config"
+)
+
+// Provider is a configured KMS master-key provider.
+type Provider interface {
+	// Name is the KMS provider name as the driver expects it: "aws", "gcp",
+	// "azure", "kmip", or "local".
+	Name() string
+
+	// Credentials returns this provider's entry in the kmsProviders map
+	// passed to ClientEncryption.
+	Credentials() map[string]interface{}
+
+	// MasterKey returns the provider-specific master key document passed to
+	// options.DataKey().SetMasterKey() when creating a new data key, or nil
+	// for providers (local) that don't need one.
+	MasterKey() interface{}
+}
+
+// Load resolves the Provider configured by cfg.KMS.Provider.
+func Load(cfg *config.Config) (Provider, error) {
+	switch cfg.KMS.Provider {
+	case "", "local":
+		return NewLocalProvider(cfg.KMS.Local)
+	case "aws":
+		return NewAWSProvider(cfg.KMS.AWS), nil
+	case "gcp":
+		return NewGCPProvider(cfg.KMS.GCP), nil
+	case "azure":
+		return NewAzureProvider(cfg.KMS.Azure), nil
+	case "kmip":
+		return NewKMIPProvider(cfg.KMS.KMIP), nil
+	case "vault":
+		return NewVaultProvider(cfg.KMS.Vault)
+	default:
+		return nil, fmt.Errorf("unknown KMS provider %q", cfg.KMS.Provider)
+	}
+}
+
+// BuildKMSProviders assembles the kmsProviders map ClientEncryption expects,
+// keyed by provider name.
+func BuildKMSProviders(providers ...Provider) map[string]map[string]interface{} {
+	kmsProviders := make(map[string]map[string]interface{}, len(providers))
+	for _, p := range providers {
+		kmsProviders[p.Name()] = p.Credentials()
+	}
+	return kmsProviders
+}
@@ -0,0 +1,35 @@
+package kms
+
+import (
+	"errors"
+
+	"csfle/internal/config"
+)
+
+// LocalProvider wraps data keys with a locally held 96-byte master key.
+// It's meant for development and testing; production setups should prefer
+// one of the cloud KMS providers or Vault.
+type LocalProvider struct {
+	masterKey string // base64-encoded
+}
+
+// NewLocalProvider builds a LocalProvider from cfg, failing if no master
+// key was configured.
+func NewLocalProvider(cfg config.LocalKMSConfig) (*LocalProvider, error) {
+	if cfg.MasterKey == "" {
+		return nil, errors.New("KMS_LOCAL_MASTER_KEY is required for the local KMS provider")
+	}
+	return &LocalProvider{masterKey: cfg.MasterKey}, nil
+}
+
+// Name implements Provider.
+func (p *LocalProvider) Name() string { return "local" }
+
+// Credentials implements Provider.
+func (p *LocalProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{"key": p.masterKey}
+}
+
+// MasterKey implements Provider. The local provider has a single master key
+// per key vault, so CreateDataKey needs no per-key master key document.
+func (p *LocalProvider) MasterKey() interface{} { return nil }
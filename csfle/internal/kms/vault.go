@@ -0,0 +1,58 @@
+package kms
+
+import (
+	"fmt"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"csfle/internal/config"
+)
+
+// VaultProvider fetches the local master key from a HashiCorp Vault KV
+// secret instead of an env var. Vault isn't a KMS provider the driver knows
+// natively, so once the key is read this behaves exactly like a local
+// provider: the driver still sees "local" in the kmsProviders map.
+type VaultProvider struct {
+	*LocalProvider
+}
+
+// NewVaultProvider logs into Vault with cfg.Token and reads the master key
+// from cfg.KeyPath/cfg.KeyField.
+func NewVaultProvider(cfg config.VaultKMSConfig) (*VaultProvider, error) {
+	if cfg.Addr == "" || cfg.Token == "" || cfg.KeyPath == "" {
+		return nil, fmt.Errorf("KMS_VAULT_ADDR, KMS_VAULT_TOKEN and KMS_VAULT_KEY_PATH are required for the vault KMS provider")
+	}
+
+	clientCfg := vaultapi.DefaultConfig()
+	clientCfg.Address = cfg.Addr
+	client, err := vaultapi.NewClient(clientCfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating vault client: %w", err)
+	}
+	client.SetToken(cfg.Token)
+
+	secret, err := client.Logical().Read(cfg.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading master key from vault: %w", err)
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, fmt.Errorf("no secret found at vault path %q", cfg.KeyPath)
+	}
+
+	// KV v2 nests the actual fields under a "data" key.
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	keyField := cfg.KeyField
+	if keyField == "" {
+		keyField = "key"
+	}
+	masterKey, ok := data[keyField].(string)
+	if !ok || masterKey == "" {
+		return nil, fmt.Errorf("vault secret at %q has no string field %q", cfg.KeyPath, keyField)
+	}
+
+	return &VaultProvider{LocalProvider: &LocalProvider{masterKey: masterKey}}, nil
+}
@@ -10,6 +10,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"csfle/internal/config"
+	"csfle/internal/keycache"
+	"csfle/internal/kms"
 )
 
 func connectMongo(uri string) *mongo.Client {
@@ -20,20 +24,17 @@ func connectMongo(uri string) *mongo.Client {
 	return client
 }
 
-func setupKMSProviders(localMasterKey string) map[string]map[string]interface{} {
-	return map[string]map[string]interface{}{
-		"local": {"key": localMasterKey},
-	}
-}
-
-func setupClientEncryption(client *mongo.Client, kmsProviders map[string]map[string]interface{}, keyVaultNamespace string) *mongo.ClientEncryption {
+// setupClientEncryption registers every configured KMS provider with a
+// single ClientEncryption instance. CreateDataKey picks which provider to
+// use per data key via its kmsProvider argument.
+func setupClientEncryption(client *mongo.Client, providers []kms.Provider, keyVaultNamespace string) *mongo.ClientEncryption {
 	clientEncryptionOpts := options.ClientEncryption().
-		SetKmsProviders(kmsProviders).
+		SetKmsProviders(kms.BuildKMSProviders(providers...)).
 		SetKeyVaultNamespace(keyVaultNamespace)
 
 	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
 	if err != nil {
-		log.Fatalf("Erro ao configurar ClientEncryption: %v", err)
+		log.Fatalf("Error configuring ClientEncryption: %v", err)
 	}
 	return clientEncryption
 }
@@ -79,7 +80,7 @@ func ensureKeyVaultIndex(keyVaultColl *mongo.Collection) {
 	}
 }
 
-func ensureDataKey(clientEncryption *mongo.ClientEncryption, keyVaultColl *mongo.Collection, keyAltName string) (primitive.Binary, error) {
+func ensureDataKey(clientEncryption *mongo.ClientEncryption, keyVaultColl *mongo.Collection, provider kms.Provider, keyAltName string) (primitive.Binary, error) {
 	var existingKey bson.M
 	err := keyVaultColl.FindOne(context.TODO(), bson.M{"keyAltNames": keyAltName}).Decode(&existingKey)
 	if err == nil {
@@ -91,14 +92,17 @@ func ensureDataKey(clientEncryption *mongo.ClientEncryption, keyVaultColl *mongo
 
 	fmt.Println("Creating new data key.")
 	dataKeyOpts := options.DataKey().SetKeyAltNames([]string{keyAltName})
-	dataKeyID, err := clientEncryption.CreateDataKey(context.TODO(), "local", dataKeyOpts)
+	if masterKey := provider.MasterKey(); masterKey != nil {
+		dataKeyOpts.SetMasterKey(masterKey)
+	}
+	dataKeyID, err := clientEncryption.CreateDataKey(context.TODO(), provider.Name(), dataKeyOpts)
 	if err != nil {
 		return primitive.Binary{}, err
 	}
 	return dataKeyID, nil
 }
 
-func encryptSalary(clientEncryption *mongo.ClientEncryption, dataKeyID primitive.Binary, salary float64) primitive.Binary {
+func encryptSalary(cache *keycache.Cache, keyAltName string, salary float64) primitive.Binary {
 	salaryInCents := int64(salary * 100)
 	rawValueType, rawValueData, err := bson.MarshalValue(salaryInCents)
 	if err != nil {
@@ -106,11 +110,7 @@ func encryptSalary(clientEncryption *mongo.ClientEncryption, dataKeyID primitive
 	}
 	rawValue := bson.RawValue{Type: rawValueType, Value: rawValueData}
 
-	encryptionOpts := options.Encrypt().
-		SetAlgorithm("AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic").
-		SetKeyID(dataKeyID)
-
-	encryptedData, err := clientEncryption.Encrypt(context.TODO(), rawValue, encryptionOpts)
+	encryptedData, err := cache.Encrypt(context.TODO(), keyAltName, rawValue, "AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic")
 	if err != nil {
 		log.Fatalf("Error encrypting salary: %v", err)
 	}
@@ -118,6 +118,106 @@ func encryptSalary(clientEncryption *mongo.ClientEncryption, dataKeyID primitive
 	return primitive.Binary{Subtype: encryptedData.Subtype, Data: encryptedData.Data}
 }
 
+// encryptSalaryQueryable converts a salary into the plain int64-cents value
+// inserted into a Queryable Encryption collection. Unlike encryptSalary, it
+// does not call ClientEncryption.Encrypt itself: the encrypted client's
+// AutoEncryptionOptions, combined with the collection's EncryptedFieldsMap,
+// encrypts the field transparently on insert and decrypts it on read.
+func encryptSalaryQueryable(salary float64) int64 {
+	return int64(salary * 100)
+}
+
+// employeeSalaryFieldsMap describes employee_data.employee_salary's
+// Queryable Encryption fields: salary is range-indexed so it supports
+// server-side $gte/$lte queries without ever leaving the server decrypted.
+func employeeSalaryFieldsMap() bson.M {
+	return bson.M{
+		"fields": []bson.M{
+			{
+				"keyId":    nil,
+				"path":     "salary",
+				"bsonType": "long",
+				"queries": []bson.M{
+					{
+						"queryType": "range",
+						"min":       int64(0),
+						"max":       int64(100_000_000),
+					},
+				},
+			},
+		},
+	}
+}
+
+// setupAutoEncryption builds the AutoEncryptionOptions used to connect a
+// Queryable Encryption client: same KMS providers and key vault namespace as
+// the explicit-encryption path, plus cryptSharedLibPath so the driver can
+// encrypt/decrypt in-process instead of spawning mongocryptd.
+func setupAutoEncryption(keyVaultNamespace string, kmsProviders map[string]map[string]interface{}, cryptSharedLibPath string) *options.AutoEncryptionOptions {
+	autoEncryptionOpts := options.AutoEncryption().
+		SetKeyVaultNamespace(keyVaultNamespace).
+		SetKmsProviders(kmsProviders)
+
+	if cryptSharedLibPath != "" {
+		autoEncryptionOpts.SetExtraOptions(map[string]interface{}{
+			"cryptSharedLibPath": cryptSharedLibPath,
+		})
+	}
+
+	return autoEncryptionOpts
+}
+
+// searchBySalaryRange runs a server-side range query against an encrypted
+// salary field, proving Queryable Encryption can filter without decrypting
+// every document client-side the way findAllAndDecryptSalaries must.
+func searchBySalaryRange(coll *mongo.Collection, minSalary, maxSalary float64) {
+	filter := bson.D{
+		{Key: "salary", Value: bson.D{
+			{Key: "$gte", Value: encryptSalaryQueryable(minSalary)},
+			{Key: "$lte", Value: encryptSalaryQueryable(maxSalary)},
+		}},
+	}
+
+	cursor, err := coll.Find(context.TODO(), filter)
+	if err != nil {
+		log.Fatalf("Error finding documents by salary range: %v", err)
+	}
+	defer cursor.Close(context.TODO())
+
+	fmt.Printf("Employees with salary between %.2f and %.2f:\n", minSalary, maxSalary)
+	for cursor.Next(context.TODO()) {
+		var foundDoc bson.M
+		if err := cursor.Decode(&foundDoc); err != nil {
+			log.Fatalf("Error decoding document: %v", err)
+		}
+		fmt.Printf("Employee: %s, Salary: %.2f\n", foundDoc["name"], float64(foundDoc["salary"].(int64))/100.0)
+	}
+
+	if err := cursor.Err(); err != nil {
+		log.Fatalf("Cursor error: %v", err)
+	}
+}
+
+// insertEmployeeDocQueryable inserts a document with a plain salary value
+// into a Queryable Encryption collection. The encrypted client's
+// AutoEncryptionOptions encrypts the salary field before it leaves the
+// driver, keyed by the collection's EncryptedFieldsMap.
+func insertEmployeeDocQueryable(coll *mongo.Collection, name, position, company string, salary int64, currency string, startDate time.Time) {
+	employeeDoc := bson.D{
+		{Key: "name", Value: name},
+		{Key: "position", Value: position},
+		{Key: "company", Value: company},
+		{Key: "salary", Value: salary},
+		{Key: "currency", Value: currency},
+		{Key: "startDate", Value: startDate},
+	}
+
+	_, err := coll.InsertOne(context.TODO(), employeeDoc)
+	if err != nil {
+		log.Fatalf("Error inserting employee document: %v", err)
+	}
+}
+
 func insertEmployeeDoc(coll *mongo.Collection, name, position, company string, salaryEncrypted primitive.Binary, currency string, startDate time.Time) {
 	employeeDoc := bson.D{
 		{Key: "name", Value: name},
@@ -134,7 +234,7 @@ func insertEmployeeDoc(coll *mongo.Collection, name, position, company string, s
 	}
 }
 
-func findAllAndDecryptSalaries(coll *mongo.Collection, clientEncryption *mongo.ClientEncryption) {
+func findAllAndDecryptSalaries(coll *mongo.Collection, cache *keycache.Cache) {
 	cursor, err := coll.Find(context.TODO(), bson.D{})
 	if err != nil {
 		log.Fatalf("Error finding documents: %v", err)
@@ -147,7 +247,7 @@ func findAllAndDecryptSalaries(coll *mongo.Collection, clientEncryption *mongo.C
 			log.Fatalf("Error decoding document: %v", err)
 		}
 
-		decrypted, err := clientEncryption.Decrypt(context.TODO(), foundDoc["salary"].(primitive.Binary))
+		decrypted, err := cache.Decrypt(context.TODO(), foundDoc["salary"].(primitive.Binary))
 		if err != nil {
 			log.Fatalf("Error decrypting salary: %v", err)
 		}
@@ -204,55 +304,123 @@ func findAllWithoutDecryption(coll *mongo.Collection) {
 	}
 }
 
+type employeeSeed struct {
+	Name      string
+	Position  string
+	Company   string
+	Salary    float64
+	Currency  string
+	StartDate time.Time
+}
+
+var sampleEmployees = []employeeSeed{
+	{"Alice Johnson", "Software Engineer", "MongoDB", 50000, "USD", time.Date(2007, time.February, 3, 0, 0, 0, 0, time.UTC)},
+	{"Bob Smith", "Product Manager", "MongoDB", 70000, "USD", time.Date(2009, time.March, 14, 0, 0, 0, 0, time.UTC)},
+	{"Charlie Brown", "Data Analyst", "MongoDB", 90000, "USD", time.Date(2011, time.June, 21, 0, 0, 0, 0, time.UTC)},
+	{"Diana Prince", "Project Manager", "MongoDB", 110000, "USD", time.Date(2012, time.July, 11, 0, 0, 0, 0, time.UTC)},
+	{"Edward Stark", "DevOps Engineer", "MongoDB", 130000, "USD", time.Date(2013, time.August, 9, 0, 0, 0, 0, time.UTC)},
+	{"Fiona Gallagher", "HR Specialist", "MongoDB", 150000, "USD", time.Date(2014, time.September, 12, 0, 0, 0, 0, time.UTC)},
+	{"George Orwell", "Security Analyst", "MongoDB", 170000, "USD", time.Date(2015, time.October, 22, 0, 0, 0, 0, time.UTC)},
+	{"Hannah Montana", "Marketing Coordinator", "MongoDB", 190000, "USD", time.Date(2016, time.November, 19, 0, 0, 0, 0, time.UTC)},
+	{"Isaac Newton", "Chief Scientist", "MongoDB", 210000, "USD", time.Date(2016, time.December, 5, 0, 0, 0, 0, time.UTC)},
+	{"Julia Roberts", "Finance Manager", "MongoDB", 250000, "USD", time.Date(2008, time.January, 28, 0, 0, 0, 0, time.UTC)},
+}
+
 func main() {
-	uri := "mongodb+srv://<user>:<pass>@demo1.f7x641l.mongodb.net/?retryWrites=true&w=majority&appName=demo1"
-	localMasterKey := "JX4CYNaw0Hu9+r3Yj7mFTWGTzQrznY8NBg1zIF3ew+5gQxk6WlrRz/tJS0n0iUojq7x+zTkQfnzDC4F+PCcUqp7pNMnRCEJxfatgW4LpNXp48QOnW2Ut72eCIpUHPS4S"
-	kmsProviders := setupKMSProviders(localMasterKey)
-	keyVaultNamespace := "encryption.__keyVault"
+	cfg := config.Load()
 
-	client := connectMongo(uri)
+	provider, err := kms.Load(cfg)
+	if err != nil {
+		log.Fatalf("Error loading KMS provider: %v", err)
+	}
+	providers := []kms.Provider{provider}
+
+	switch cfg.EncryptionMode {
+	case "queryable":
+		runQueryableDemo(cfg, provider, providers)
+	default:
+		runDeterministicDemo(cfg, provider, providers)
+	}
+}
+
+// runDeterministicDemo encrypts salary with the AEAD deterministic algorithm,
+// which only supports equality lookups, and explicitly encrypts/decrypts
+// every value through ClientEncryption.
+func runDeterministicDemo(cfg *config.Config, provider kms.Provider, providers []kms.Provider) {
+	client := connectMongo(cfg.MongoDB.URI)
 	defer client.Disconnect(context.TODO())
 
-	databaseName := "employee_data"
-	collectionName := "employee_salary"
-	coll := client.Database(databaseName).Collection(collectionName)
+	coll := client.Database(cfg.MongoDB.Database).Collection(cfg.MongoDB.Collection)
 	_ = coll.Drop(context.TODO())
-	keyVaultColl := client.Database("encryption").Collection("__keyVault")
+	keyVaultColl := client.Database(cfg.MongoDB.KeyVaultDatabase).Collection(cfg.MongoDB.KeyVaultCollection)
 	ensureKeyVaultIndex(keyVaultColl)
 
-	clientEncryption := setupClientEncryption(client, kmsProviders, keyVaultNamespace)
+	clientEncryption := setupClientEncryption(client, providers, cfg.MongoDB.KeyVaultNamespace())
 	defer clientEncryption.Close(context.TODO())
 
-	dataKeyID, err := ensureDataKey(clientEncryption, keyVaultColl, "go_encryption_example")
-	if err != nil {
+	keyAltName := "go_encryption_example"
+	if _, err := ensureDataKey(clientEncryption, keyVaultColl, provider, keyAltName); err != nil {
 		log.Fatalf("Error ensuring data key: %v", err)
 	}
 
-	employees := []struct {
-		Name      string
-		Position  string
-		Company   string
-		Salary    float64
-		Currency  string
-		StartDate time.Time
-	}{
-		{"Alice Johnson", "Software Engineer", "MongoDB", 50000, "USD", time.Date(2007, time.February, 3, 0, 0, 0, 0, time.UTC)},
-		{"Bob Smith", "Product Manager", "MongoDB", 70000, "USD", time.Date(2009, time.March, 14, 0, 0, 0, 0, time.UTC)},
-		{"Charlie Brown", "Data Analyst", "MongoDB", 90000, "USD", time.Date(2011, time.June, 21, 0, 0, 0, 0, time.UTC)},
-		{"Diana Prince", "Project Manager", "MongoDB", 110000, "USD", time.Date(2012, time.July, 11, 0, 0, 0, 0, time.UTC)},
-		{"Edward Stark", "DevOps Engineer", "MongoDB", 130000, "USD", time.Date(2013, time.August, 9, 0, 0, 0, 0, time.UTC)},
-		{"Fiona Gallagher", "HR Specialist", "MongoDB", 150000, "USD", time.Date(2014, time.September, 12, 0, 0, 0, 0, time.UTC)},
-		{"George Orwell", "Security Analyst", "MongoDB", 170000, "USD", time.Date(2015, time.October, 22, 0, 0, 0, 0, time.UTC)},
-		{"Hannah Montana", "Marketing Coordinator", "MongoDB", 190000, "USD", time.Date(2016, time.November, 19, 0, 0, 0, 0, time.UTC)},
-		{"Isaac Newton", "Chief Scientist", "MongoDB", 210000, "USD", time.Date(2016, time.December, 5, 0, 0, 0, 0, time.UTC)},
-		{"Julia Roberts", "Finance Manager", "MongoDB", 250000, "USD", time.Date(2008, time.January, 28, 0, 0, 0, 0, time.UTC)},
-	}
-
-	for _, emp := range employees {
-		encryptedSalary := encryptSalary(clientEncryption, dataKeyID, emp.Salary)
+	cache := keycache.NewCache(clientEncryption, keyVaultColl, cfg.KeyCache.TTL)
+	defer cache.StopGC()
+
+	for _, emp := range sampleEmployees {
+		encryptedSalary := encryptSalary(cache, keyAltName, emp.Salary)
 		insertEmployeeDoc(coll, emp.Name, emp.Position, emp.Company, encryptedSalary, emp.Currency, emp.StartDate)
 	}
 
-	findAllAndDecryptSalaries(coll, clientEncryption)
+	findAllAndDecryptSalaries(coll, cache)
 	findAllWithoutDecryption(coll)
 }
+
+// runQueryableDemo encrypts salary as a Queryable Encryption range-indexed
+// field so the server can evaluate $gte/$lte filters without ever seeing the
+// plaintext. Encryption and decryption both happen automatically via the
+// client's AutoEncryptionOptions, not through explicit Encrypt/Decrypt calls.
+func runQueryableDemo(cfg *config.Config, provider kms.Provider, providers []kms.Provider) {
+	kmsProviders := kms.BuildKMSProviders(providers...)
+	keyVaultNamespace := cfg.MongoDB.KeyVaultNamespace()
+
+	autoEncryptionOpts := setupAutoEncryption(keyVaultNamespace, kmsProviders, cfg.CryptSharedLibPath)
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(cfg.MongoDB.URI).SetAutoEncryptionOptions(autoEncryptionOpts))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer client.Disconnect(context.TODO())
+
+	keyVaultColl := client.Database(cfg.MongoDB.KeyVaultDatabase).Collection(cfg.MongoDB.KeyVaultCollection)
+	_ = keyVaultColl.Drop(context.TODO())
+	db := client.Database(cfg.MongoDB.Database)
+	_ = db.Collection(cfg.MongoDB.Collection).Drop(context.TODO())
+
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(kmsProviders).
+		SetKeyVaultNamespace(keyVaultNamespace)
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		log.Fatalf("Error configuring ClientEncryption: %v", err)
+	}
+	defer clientEncryption.Close(context.TODO())
+
+	masterKey := provider.MasterKey()
+	if masterKey == nil {
+		masterKey = map[string]string{}
+	}
+	createCollectionOpts := options.CreateCollection().SetEncryptedFields(employeeSalaryFieldsMap())
+	_, _, err = clientEncryption.CreateEncryptedCollection(
+		context.TODO(), db, cfg.MongoDB.Collection, createCollectionOpts, provider.Name(), masterKey,
+	)
+	if err != nil {
+		log.Fatalf("Error creating encrypted collection: %v", err)
+	}
+	fmt.Printf("Encrypted collection '%s' created.\n", cfg.MongoDB.Collection)
+
+	coll := db.Collection(cfg.MongoDB.Collection)
+	for _, emp := range sampleEmployees {
+		insertEmployeeDocQueryable(coll, emp.Name, emp.Position, emp.Company, encryptSalaryQueryable(emp.Salary), emp.Currency, emp.StartDate)
+	}
+
+	searchBySalaryRange(coll, 100000, 200000)
+}
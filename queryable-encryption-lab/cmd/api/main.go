@@ -4,15 +4,20 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
+	"queryable-encryption-lab/internal/audit"
+	"queryable-encryption-lab/internal/auth"
 	"queryable-encryption-lab/internal/config"
 	"queryable-encryption-lab/internal/encryption"
 	"queryable-encryption-lab/internal/handler"
 	"queryable-encryption-lab/internal/middleware"
+	"queryable-encryption-lab/internal/migration"
+	_ "queryable-encryption-lab/internal/migrations" // registers migrations via init()
 	"queryable-encryption-lab/internal/repository"
 	"queryable-encryption-lab/internal/service"
 
@@ -23,6 +28,9 @@ import (
 )
 
 func main() {
+	migrateOnly := flag.Bool("migrate-only", false, "run pending schema migrations then exit, without starting the server")
+	flag.Parse()
+
 	// Load configuration
 	cfg := config.Load()
 	gin.SetMode(cfg.Server.GinMode)
@@ -32,11 +40,10 @@ func main() {
 	log.Println("===========================================")
 
 	// Initialize encryption manager
-	encryptionMgr := encryption.NewEncryptionManager(
-		cfg.KeyVaultNamespace(),
-		cfg.Encryption.LocalMasterKeyPath,
-		cfg.Encryption.CryptSharedLibPath,
-	)
+	encryptionMgr, err := encryption.NewEncryptionManager(cfg.KeyVaultNamespace(), cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Error initializing encryption manager: %v", err)
+	}
 
 	// Connect to MongoDB with auto-encryption
 	mongoClient, err := connectMongoDB(cfg, encryptionMgr)
@@ -68,9 +75,73 @@ func main() {
 	pingCancel()
 	log.Println("MongoDB connection verified after encrypted collection setup")
 
+	// Run pending schema migrations (new encrypted fields, collection
+	// reshapes, etc.) before anything else touches the collection.
+	migrationRunner := migration.NewRunner(mongoClient, db, encryptionMgr)
+	migrateCtx, migrateCancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	err = migrationRunner.Run(migrateCtx)
+	migrateCancel()
+	if err != nil {
+		log.Fatalf("Error running migrations: %v", err)
+	}
+	log.Println("Migrations up to date")
+
+	if *migrateOnly {
+		log.Println("--migrate-only set, exiting without starting the server")
+		return
+	}
+
+	// Initialize the explicit-encryption key manager: named DEKs, explicit
+	// Encrypt/Decrypt, and master-key rotation, alongside the auto-encrypted
+	// path mongoClient already handles transparently.
+	keyMgr, err := encryption.NewKeyManager(mongoClient, cfg.KeyVaultNamespace(), encryptionMgr)
+	if err != nil {
+		log.Fatalf("Error initializing key manager: %v", err)
+	}
+	defer func() {
+		if err := keyMgr.Close(context.Background()); err != nil {
+			log.Printf("Error closing key manager: %v", err)
+		}
+	}()
+	keyHandler := handler.NewKeyHandler(keyMgr, encryptionMgr.Provider)
+
+	// Initialize the explicit encryptor: a lighter-weight Encrypt/Decrypt for
+	// callers outside the auto-encryption path (here, the employee service's
+	// EncryptSSNForLookup/Decrypt) that need to build a $match against an
+	// encrypted field or read one back, without the key-vault/rotation
+	// bookkeeping keyMgr carries.
+	explicitEncryptor, err := encryption.NewExplicitEncryptor(mongoClient, cfg.KeyVaultNamespace(), encryptionMgr)
+	if err != nil {
+		log.Fatalf("Error initializing explicit encryptor: %v", err)
+	}
+	defer func() {
+		if err := explicitEncryptor.Close(context.Background()); err != nil {
+			log.Printf("Error closing explicit encryptor: %v", err)
+		}
+	}()
+
+	if cfg.Encryption.RotateOnStart {
+		log.Println("KMS_ROTATE=true, rewrapping data encryption keys...")
+		rotateCtx, rotateCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		touched, err := keyMgr.RotateMasterKey(rotateCtx, encryptionMgr.Provider, "startup", false)
+		rotateCancel()
+		if err != nil {
+			log.Fatalf("Error rotating master key: %v", err)
+		}
+		log.Printf("Rewrapped %d data encryption keys", touched)
+	}
+
+	// Initialize the audit log, used to record who changed what on every
+	// employee mutation and served back via the admin-only /audit endpoint
+	auditor := audit.NewMongoAuditor(db.Collection("audit_log"))
+	auditHandler := handler.NewAuditHandler(auditor)
+
 	// Initialize layers
-	employeeRepo := repository.NewEmployeeRepository(db, cfg.MongoDB.Collection)
-	employeeService := service.NewEmployeeService(employeeRepo)
+	employeeRepo := repository.NewEmployeeRepository(db, cfg.MongoDB.Collection,
+		repository.WithSeedConcurrency(cfg.SeedWorkers, cfg.SeedBatchSize))
+	employeeService := service.NewEmployeeService(employeeRepo,
+		service.WithAuditor(auditor),
+		service.WithExplicitEncryptor(explicitEncryptor))
 	employeeHandler := handler.NewEmployeeHandler(employeeService)
 
 	// Seed database with sample data (optional - controlled by env var)
@@ -79,7 +150,7 @@ func main() {
 	}
 
 	// Setup router
-	router := setupRouter(cfg, employeeHandler)
+	router := setupRouter(cfg, employeeHandler, auditHandler, keyHandler)
 
 	// Start server
 	log.Printf("Server starting on http://localhost:%s", cfg.Server.Port)
@@ -99,6 +170,14 @@ func connectMongoDB(cfg *config.Config, encryptionMgr *encryption.EncryptionMana
 		ApplyURI(cfg.MongoDB.URI).
 		SetAutoEncryptionOptions(encryptionMgr.GetAutoEncryptionOptions())
 
+	credential, err := auth.Credential(cfg.MongoDB)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure MongoDB auth: %w", err)
+	}
+	if credential != nil {
+		clientOptions.SetAuth(*credential)
+	}
+
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to MongoDB: %w", err)
@@ -135,11 +214,13 @@ func seedDatabaseIfRequested(ctx context.Context, repo repository.EmployeeReposi
 		seedCount = 100 // Default
 	}
 
-	if err := repo.SeedEmployees(ctx, seedCount); err != nil {
+	report, err := repo.SeedEmployees(ctx, seedCount)
+	if err != nil {
 		return fmt.Errorf("error seeding employees: %w", err)
 	}
 
-	log.Println("Database seeding complete!")
+	log.Printf("Database seeding complete: %d inserted, %d failed, took %v",
+		report.Inserted, report.Failed, report.Duration)
 	return nil
 }
 
@@ -202,11 +283,12 @@ func disconnectMongoDB(client *mongo.Client) {
 }
 
 // setupRouter configures the HTTP router
-func setupRouter(cfg *config.Config, employeeHandler *handler.EmployeeHandler) *gin.Engine {
+func setupRouter(cfg *config.Config, employeeHandler *handler.EmployeeHandler, auditHandler *handler.AuditHandler, keyHandler *handler.KeyHandler) *gin.Engine {
 	router := gin.Default()
 
 	// CORS middleware
 	router.Use(middleware.CORSMiddleware(cfg.CORS.AllowedOrigins))
+	router.Use(middleware.AuthContext()) // Stashes doer/trace IDs for audit attribution
 
 	// Serve static files
 	router.Static("/static", "./web/static")
@@ -238,6 +320,18 @@ func setupRouter(cfg *config.Config, employeeHandler *handler.EmployeeHandler) *
 				search.GET("/advanced", employeeHandler.AdvancedSearch)
 			}
 		}
+
+		// Audit routes (admin-only)
+		api.GET("/audit", middleware.RequireAdmin(), auditHandler.GetAuditLog)
+
+		// Key management routes (admin-only)
+		keys := api.Group("/admin/keys", middleware.RequireAdmin())
+		{
+			keys.POST("/rotate", keyHandler.RotateKeys)
+			keys.GET("", keyHandler.ListDataKeys)
+			keys.GET("/:altName", keyHandler.GetDataKey)
+			keys.DELETE("/:altName", keyHandler.DeleteDataKey)
+		}
 	}
 
 	return router
@@ -0,0 +1,73 @@
+// Command rotate-keys rewraps every data encryption key in the Queryable
+// Encryption HR Platform's key vault under the currently configured KMS
+// master key, recording the run to the key vault's __keyRotationLog
+// collection.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"queryable-encryption-lab/internal/config"
+	"queryable-encryption-lab/internal/encryption"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func main() {
+	operator := flag.String("operator", "", "identifier of the person/system running this rotation, recorded in the rotation log")
+	dryRun := flag.Bool("dry-run", false, "report how many data keys would be rewrapped without rewrapping anything")
+	flag.Parse()
+
+	if *operator == "" {
+		log.Fatal("-operator is required")
+	}
+
+	cfg := config.Load()
+
+	encryptionMgr, err := encryption.NewEncryptionManager(cfg.KeyVaultNamespace(), cfg.Encryption)
+	if err != nil {
+		log.Fatalf("Error initializing encryption manager: %v", err)
+	}
+
+	ctx := context.Background()
+
+	// This CLI only touches the key vault, so it connects without
+	// auto-encryption - unlike cmd/api, it never reads or writes the
+	// employees collection.
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDB.URI))
+	if err != nil {
+		log.Fatalf("Error connecting to MongoDB: %v", err)
+	}
+	defer func() {
+		disconnectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.Disconnect(disconnectCtx); err != nil {
+			log.Printf("Error disconnecting from MongoDB: %v", err)
+		}
+	}()
+
+	keyMgr, err := encryption.NewKeyManager(client, cfg.KeyVaultNamespace(), encryptionMgr)
+	if err != nil {
+		log.Fatalf("Error initializing key manager: %v", err)
+	}
+	defer func() {
+		if err := keyMgr.Close(context.Background()); err != nil {
+			log.Printf("Error closing key manager: %v", err)
+		}
+	}()
+
+	touched, err := keyMgr.RotateMasterKey(ctx, encryptionMgr.Provider, *operator, *dryRun)
+	if err != nil {
+		log.Fatalf("Rotation failed: %v", err)
+	}
+
+	if *dryRun {
+		log.Printf("Dry run: %d data encryption keys would be rewrapped", touched)
+		return
+	}
+	log.Printf("Rewrapped %d data encryption keys", touched)
+}
@@ -0,0 +1,264 @@
+// Package auth builds the MongoDB client credential for authentication
+// modes beyond a URI with embedded credentials. Today that's
+// MONGODB-OIDC, covering both workload-identity machine flows (Azure,
+// GCP, AWS) and the interactive human device-code flow.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"queryable-encryption-lab/internal/config"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// defaultCallbackTimeout bounds an OIDC token request when
+// OIDCConfig.CallbackTimeoutSeconds is unset or non-positive.
+const defaultCallbackTimeout = 30 * time.Second
+
+// Credential builds the options.Credential for cfg.AuthMechanism. It
+// returns (nil, nil) when AuthMechanism isn't set, so callers can apply the
+// result to options.Client().SetAuth unconditionally. The driver itself
+// retries OIDCMachineCallback/OIDCHumanCallback on ReauthenticationRequired,
+// so callbacks just need to hand back a fresh token on every call.
+func Credential(cfg config.MongoDBConfig) (*options.Credential, error) {
+	if cfg.AuthMechanism == "" {
+		return nil, nil
+	}
+	if cfg.AuthMechanism != "oidc" {
+		return nil, fmt.Errorf("unknown AUTH_MECHANISM %q: use oidc or leave unset", cfg.AuthMechanism)
+	}
+
+	timeout := callbackTimeout(cfg.OIDC)
+
+	switch cfg.OIDC.Environment {
+	case "azure", "gcp":
+		// The driver calls the cloud metadata endpoint itself for these
+		// built-in environments once ENVIRONMENT/TOKEN_RESOURCE are set.
+		return &options.Credential{
+			AuthMechanism: "MONGODB-OIDC",
+			AuthMechanismProperties: map[string]string{
+				"ENVIRONMENT":    cfg.OIDC.Environment,
+				"TOKEN_RESOURCE": cfg.OIDC.TokenResource,
+			},
+		}, nil
+	case "test":
+		// The driver's "test" environment talks to a local OIDC test
+		// server; no custom callback needed.
+		return &options.Credential{
+			AuthMechanism:           "MONGODB-OIDC",
+			AuthMechanismProperties: map[string]string{"ENVIRONMENT": "test"},
+		}, nil
+	case "aws":
+		// The driver has no built-in "aws" ENVIRONMENT, so read the Web
+		// Identity token EKS/ECS inject ourselves and hand it over through
+		// a machine callback.
+		return &options.Credential{
+			AuthMechanism:       "MONGODB-OIDC",
+			OIDCMachineCallback: awsMachineCallback(),
+		}, nil
+	case "":
+		// No workload identity configured: fall back to the interactive
+		// human flow, authenticating via a device-code grant.
+		return &options.Credential{
+			AuthMechanism:     "MONGODB-OIDC",
+			OIDCHumanCallback: deviceCodeHumanCallback(timeout),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown OIDC_ENVIRONMENT %q: use azure, gcp, aws, test, or leave unset for the human device-code flow", cfg.OIDC.Environment)
+	}
+}
+
+func callbackTimeout(cfg config.OIDCConfig) time.Duration {
+	if cfg.CallbackTimeoutSeconds <= 0 {
+		return defaultCallbackTimeout
+	}
+	return time.Duration(cfg.CallbackTimeoutSeconds) * time.Second
+}
+
+// awsMachineCallback reads the Kubernetes/ECS Web Identity token from disk
+// on every call, so rotated tokens and ReauthenticationRequired retries
+// always pick up the latest value. Reading a local file is never slow
+// enough to need the callback timeout the other flows apply to network
+// calls.
+func awsMachineCallback() options.OIDCCallback {
+	return func(_ context.Context, _ *options.OIDCArgs) (*options.OIDCCredential, error) {
+		path := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+		if path == "" {
+			return nil, fmt.Errorf("AWS_WEB_IDENTITY_TOKEN_FILE is not set; required for OIDC_ENVIRONMENT=aws")
+		}
+
+		token, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read AWS web identity token: %w", err)
+		}
+
+		return &options.OIDCCredential{AccessToken: strings.TrimSpace(string(token))}, nil
+	}
+}
+
+// oidcDiscoveryDoc is the subset of an OpenID Connect discovery document
+// (issuer/.well-known/openid-configuration) the device-code flow needs.
+type oidcDiscoveryDoc struct {
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+}
+
+// deviceAuthorizationResponse is RFC 8628's device authorization response.
+type deviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceTokenResponse is RFC 8628's token response, polled for on the
+// device authorization grant.
+type deviceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	Error       string `json:"error"`
+}
+
+// deviceCodeHumanCallback authenticates an operator via the OAuth 2.0
+// device authorization grant (RFC 8628): it discovers the IdP's endpoints
+// from args.IDPInfo.Issuer, requests a device code, prints the
+// verification URL for the operator to open, and polls the token endpoint
+// until they approve it or the callback timeout elapses. The grant's own
+// expires_in is not consulted.
+func deviceCodeHumanCallback(timeout time.Duration) options.OIDCCallback {
+	return func(ctx context.Context, args *options.OIDCArgs) (*options.OIDCCredential, error) {
+		if args.IDPInfo == nil || args.IDPInfo.Issuer == "" {
+			return nil, fmt.Errorf("server did not return IdP info for the OIDC human flow")
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		client := &http.Client{Timeout: timeout}
+
+		discovery, err := fetchDiscoveryDoc(ctx, client, args.IDPInfo.Issuer)
+		if err != nil {
+			return nil, err
+		}
+
+		auth, err := requestDeviceCode(ctx, client, discovery.DeviceAuthorizationEndpoint, args.IDPInfo)
+		if err != nil {
+			return nil, err
+		}
+
+		verificationURL := auth.VerificationURIComplete
+		if verificationURL == "" {
+			verificationURL = auth.VerificationURI
+		}
+		fmt.Printf("To authenticate, visit %s and enter code %s\n", verificationURL, auth.UserCode)
+
+		return pollDeviceToken(ctx, client, discovery.TokenEndpoint, auth)
+	}
+}
+
+func fetchDiscoveryDoc(ctx context.Context, client *http.Client, issuer string) (*oidcDiscoveryDoc, error) {
+	discoveryURL := strings.TrimRight(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build OIDC discovery request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch OIDC discovery document: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var doc oidcDiscoveryDoc
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to decode OIDC discovery document: %w", err)
+	}
+	return &doc, nil
+}
+
+func requestDeviceCode(ctx context.Context, client *http.Client, endpoint string, idp *options.IDPInfo) (*deviceAuthorizationResponse, error) {
+	form := url.Values{"client_id": {idp.ClientID}}
+	if len(idp.RequestScopes) > 0 {
+		form.Set("scope", strings.Join(idp.RequestScopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to build device authorization request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to request device code: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var auth deviceAuthorizationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&auth); err != nil {
+		return nil, fmt.Errorf("unable to decode device authorization response: %w", err)
+	}
+	return &auth, nil
+}
+
+func pollDeviceToken(ctx context.Context, client *http.Client, endpoint string, auth *deviceAuthorizationResponse) (*options.OIDCCredential, error) {
+	interval := time.Duration(auth.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {auth.DeviceCode},
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for device-code authorization: %w", ctx.Err())
+		case <-ticker.C:
+			token, err := requestDeviceToken(ctx, client, endpoint, form)
+			if err != nil {
+				return nil, err
+			}
+			if token.Error == "authorization_pending" || token.Error == "slow_down" {
+				continue
+			}
+			if token.Error != "" {
+				return nil, fmt.Errorf("device-code authorization failed: %s", token.Error)
+			}
+			return &options.OIDCCredential{AccessToken: token.AccessToken}, nil
+		}
+	}
+}
+
+func requestDeviceToken(ctx context.Context, client *http.Client, endpoint string, form url.Values) (*deviceTokenResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("unable to build device token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to poll device token endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var token deviceTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, fmt.Errorf("unable to decode device token response: %w", err)
+	}
+	return &token, nil
+}
@@ -5,27 +5,43 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"runtime"
 	"strings"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
 // Config holds all application configuration
 type Config struct {
-	MongoDB      MongoDBConfig
-	Encryption   EncryptionConfig
-	Server       ServerConfig
-	CORS              CORSConfig
-	SeedDatabase      bool
-	SeedCount         int
-	DropCollections   bool
+	MongoDB         MongoDBConfig
+	Encryption      EncryptionConfig
+	Server          ServerConfig
+	CORS            CORSConfig
+	SeedDatabase    bool
+	SeedCount       int
+	SeedBatchSize   int
+	SeedWorkers     int
+	DropCollections bool
 }
 
 // MongoDBConfig contains MongoDB connection settings
 type MongoDBConfig struct {
-	URI        string
-	Database   string
-	Collection string
+	URI           string
+	Database      string
+	Collection    string
+	AuthMechanism string // "" (credentials embedded in URI, default) or "oidc"
+	OIDC          OIDCConfig
+}
+
+// OIDCConfig configures MONGODB-OIDC authentication, used when
+// MongoDBConfig.AuthMechanism is "oidc". Environment selects a
+// workload-identity machine flow (azure, gcp, aws, test); leaving it empty
+// selects the interactive human device-code flow instead.
+type OIDCConfig struct {
+	Environment            string
+	TokenResource          string
+	CallbackTimeoutSeconds int
 }
 
 // EncryptionConfig contains encryption-related settings
@@ -34,6 +50,61 @@ type EncryptionConfig struct {
 	KeyVaultCollection string
 	LocalMasterKeyPath string
 	CryptSharedLibPath string
+	KMS                KMSConfig
+	// RotateOnStart, when true, rewraps every data encryption key in the
+	// key vault under the currently configured KMS master key at startup.
+	// Set it after rotating a CMK's key material (or its keyARN/keyVersion
+	// env vars) so existing DEKs move to the new key instead of staying
+	// wrapped under the old one.
+	RotateOnStart bool
+}
+
+// KMSConfig selects and configures the Queryable Encryption KMS provider.
+// Provider picks which of the provider-specific blocks is actually
+// required; every other block is simply ignored.
+type KMSConfig struct {
+	Provider string // local (default), aws, azure, gcp, or kmip
+
+	AWS   AWSKMSConfig
+	Azure AzureKMSConfig
+	GCP   GCPKMSConfig
+	KMIP  KMIPKMSConfig
+}
+
+// AWSKMSConfig holds the settings for the "aws" KMS provider.
+type AWSKMSConfig struct {
+	Region          string
+	KeyARN          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AzureKMSConfig holds the settings for the "azure" KMS provider.
+type AzureKMSConfig struct {
+	TenantID         string
+	ClientID         string
+	ClientSecret     string
+	KeyVaultEndpoint string
+	KeyName          string
+	KeyVersion       string
+}
+
+// GCPKMSConfig holds the settings for the "gcp" KMS provider.
+type GCPKMSConfig struct {
+	Email      string
+	PrivateKey string
+	ProjectID  string
+	Location   string
+	KeyRing    string
+	KeyName    string
+	KeyVersion string
+}
+
+// KMIPKMSConfig holds the settings for the "kmip" KMS provider.
+type KMIPKMSConfig struct {
+	Endpoint string
+	KeyID    string
 }
 
 // ServerConfig contains server settings
@@ -47,24 +118,68 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
-// Load reads configuration from environment variables
+// Load reads configuration from environment variables, falling back to a
+// YAML file for the KMS section when KMS_CONFIG_FILE is set. This lets dev
+// keep using a .env with a local master key path while a prod deployment
+// mounts a YAML file naming its cloud KMS instead of setting a dozen
+// individual KMS_* env vars.
 func Load() *Config {
 	// Load .env file if it exists
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	kmsFile := loadKMSConfigFile(getEnv("KMS_CONFIG_FILE", ""))
+
 	return &Config{
 		MongoDB: MongoDBConfig{
-			URI:        getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-			Database:   getEnv("MONGODB_DATABASE", "hr_encrypted"),
-			Collection: getEnv("MONGODB_COLLECTION", "employees"),
+			URI:           getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+			Database:      getEnv("MONGODB_DATABASE", "hr_encrypted"),
+			Collection:    getEnv("MONGODB_COLLECTION", "employees"),
+			AuthMechanism: getEnv("AUTH_MECHANISM", ""),
+			OIDC: OIDCConfig{
+				Environment:            getEnv("OIDC_ENVIRONMENT", ""),
+				TokenResource:          getEnv("OIDC_TOKEN_RESOURCE", ""),
+				CallbackTimeoutSeconds: getEnvAsInt("OIDC_CALLBACK_TIMEOUT", 30),
+			},
 		},
 		Encryption: EncryptionConfig{
 			KeyVaultDatabase:   getEnv("KEY_VAULT_DATABASE", "encryption"),
 			KeyVaultCollection: getEnv("KEY_VAULT_COLLECTION", "__keyVault"),
 			LocalMasterKeyPath: getEnv("LOCAL_MASTER_KEY_PATH", "./local_master_key.txt"),
 			CryptSharedLibPath: getEnv("CRYPT_SHARED_LIB_PATH", ""),
+			KMS: KMSConfig{
+				Provider: getEnvOr("KMS_PROVIDER", kmsFile.Provider, "local"),
+				AWS: AWSKMSConfig{
+					Region:          getEnvOr("KMS_AWS_REGION", kmsFile.AWS.Region, ""),
+					KeyARN:          getEnvOr("KMS_AWS_KEY_ARN", kmsFile.AWS.KeyARN, ""),
+					AccessKeyID:     getEnvOr("KMS_AWS_ACCESS_KEY_ID", kmsFile.AWS.AccessKeyID, ""),
+					SecretAccessKey: getEnvOr("KMS_AWS_SECRET_ACCESS_KEY", kmsFile.AWS.SecretAccessKey, ""),
+					SessionToken:    getEnvOr("KMS_AWS_SESSION_TOKEN", kmsFile.AWS.SessionToken, ""),
+				},
+				Azure: AzureKMSConfig{
+					TenantID:         getEnvOr("KMS_AZURE_TENANT_ID", kmsFile.Azure.TenantID, ""),
+					ClientID:         getEnvOr("KMS_AZURE_CLIENT_ID", kmsFile.Azure.ClientID, ""),
+					ClientSecret:     getEnvOr("KMS_AZURE_CLIENT_SECRET", kmsFile.Azure.ClientSecret, ""),
+					KeyVaultEndpoint: getEnvOr("KMS_AZURE_KEY_VAULT_ENDPOINT", kmsFile.Azure.KeyVaultEndpoint, ""),
+					KeyName:          getEnvOr("KMS_AZURE_KEY_NAME", kmsFile.Azure.KeyName, ""),
+					KeyVersion:       getEnvOr("KMS_AZURE_KEY_VERSION", kmsFile.Azure.KeyVersion, ""),
+				},
+				GCP: GCPKMSConfig{
+					Email:      getEnvOr("KMS_GCP_EMAIL", kmsFile.GCP.Email, ""),
+					PrivateKey: getEnvOr("KMS_GCP_PRIVATE_KEY", kmsFile.GCP.PrivateKey, ""),
+					ProjectID:  getEnvOr("KMS_GCP_PROJECT_ID", kmsFile.GCP.ProjectID, ""),
+					Location:   getEnvOr("KMS_GCP_LOCATION", kmsFile.GCP.Location, ""),
+					KeyRing:    getEnvOr("KMS_GCP_KEY_RING", kmsFile.GCP.KeyRing, ""),
+					KeyName:    getEnvOr("KMS_GCP_KEY_NAME", kmsFile.GCP.KeyName, ""),
+					KeyVersion: getEnvOr("KMS_GCP_KEY_VERSION", kmsFile.GCP.KeyVersion, ""),
+				},
+				KMIP: KMIPKMSConfig{
+					Endpoint: getEnvOr("KMS_KMIP_ENDPOINT", kmsFile.KMIP.Endpoint, ""),
+					KeyID:    getEnvOr("KMS_KMIP_KEY_ID", kmsFile.KMIP.KeyID, ""),
+				},
+			},
+			RotateOnStart: getEnvAsBool("KMS_ROTATE", false),
 		},
 		Server: ServerConfig{
 			Port:    getEnv("SERVER_PORT", "8080"),
@@ -75,6 +190,8 @@ func Load() *Config {
 		},
 		SeedDatabase:    getEnvAsBool("SEED_DATABASE", true),
 		SeedCount:       getEnvAsInt("SEED_COUNT", 100),
+		SeedBatchSize:   getEnvAsInt("SEED_BATCH_SIZE", 500),
+		SeedWorkers:     getEnvAsInt("SEED_WORKERS", runtime.NumCPU()),
 		DropCollections: getEnvAsBool("DROP_COLLECTIONS", false),
 	}
 }
@@ -87,6 +204,47 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvOr is getEnv with an extra fallback between the env var and the
+// hardcoded default: a value sourced from the KMS_CONFIG_FILE YAML file, if
+// one was loaded. Precedence is env var, then file value, then default.
+func getEnvOr(key, fileValue, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	if fileValue != "" {
+		return fileValue
+	}
+	return defaultValue
+}
+
+// loadKMSConfigFile reads a YAML file's "kms" section into a KMSConfig,
+// used as a fallback source for getEnvOr. An empty path, a missing file, or
+// a parse error all just mean "no file values available" - this is an
+// optional convenience for prod deployments that'd rather mount one YAML
+// file naming their cloud KMS than set a dozen KMS_* env vars, not a
+// required configuration mechanism.
+func loadKMSConfigFile(path string) KMSConfig {
+	if path == "" {
+		return KMSConfig{}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("Warning: unable to read KMS_CONFIG_FILE %q: %v", path, err)
+		return KMSConfig{}
+	}
+
+	var file struct {
+		KMS KMSConfig `yaml:"kms"`
+	}
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		log.Printf("Warning: unable to parse KMS_CONFIG_FILE %q: %v", path, err)
+		return KMSConfig{}
+	}
+
+	return file.KMS
+}
+
 // getEnvAsSlice gets an environment variable as a slice
 func getEnvAsSlice(key string, defaultValue []string, separator string) []string {
 	value := os.Getenv(key)
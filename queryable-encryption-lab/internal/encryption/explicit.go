@@ -0,0 +1,140 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Queryable Encryption explicit-encryption algorithms. Unindexed is passed
+// to ExplicitEncryptor.Encrypt for a field that is encrypted but never
+// queried. Indexed is used internally by EncryptForQuery, the only way to
+// produce a usable equality-queryable payload: it additionally needs
+// SetQueryType and a contention factor matching the field, which Encrypt
+// has no way to supply. There's no Range equivalent here - Range explicit
+// encryption also needs SetRangeOptions (min/max/precision matching the
+// field's entry in GetEncryptedFieldsMap), and nothing in this codebase
+// runs a range lookup outside the auto-encryption path, so it isn't wired
+// up.
+const (
+	AlgorithmIndexed   = "Indexed"
+	AlgorithmUnindexed = "Unindexed"
+)
+
+// DefaultContentionFactor is the contention factor EncryptForQuery callers
+// should pass for a field whose "queries" entry in GetEncryptedFieldsMap
+// doesn't set "contention" - every field defined there today, and the
+// driver's own default. It must match the contention the field was
+// created with, or the produced payload won't match what's stored.
+const DefaultContentionFactor = 8
+
+// ExplicitEncryptor wraps mongo.ClientEncryption.Encrypt/Decrypt for callers
+// outside the auto-encryption path - batch jobs, migrations, analytics
+// exporters, message publishers - that need to encrypt or decrypt one value
+// by hand rather than through a mongo.Client configured with
+// SetAutoEncryptionOptions. Unlike KeyManager, it carries no key-vault
+// collection handle or rotation bookkeeping; it only wraps ClientEncryption.
+type ExplicitEncryptor struct {
+	clientEncryption *mongo.ClientEncryption
+}
+
+// NewExplicitEncryptor builds an ExplicitEncryptor backed by its own
+// ClientEncryption, talking to the same key vault namespace and KMS
+// providers as em, using client for key-vault reads.
+func NewExplicitEncryptor(client *mongo.Client, keyVaultNamespace string, em *EncryptionManager) (*ExplicitEncryptor, error) {
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(em.KmsProviders).
+		SetKeyVaultNamespace(keyVaultNamespace)
+
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client encryption: %w", err)
+	}
+
+	return &ExplicitEncryptor{clientEncryption: clientEncryption}, nil
+}
+
+// Close releases the underlying ClientEncryption's resources.
+func (e *ExplicitEncryptor) Close(ctx context.Context) error {
+	return e.clientEncryption.Close(ctx)
+}
+
+// Encrypt encrypts value under the DEK named keyAltName using algorithm,
+// returning a primitive.Binary suitable for storage in a document. It is
+// not valid for AlgorithmIndexed - that and the unexposed "Range" algorithm
+// need a contention factor the server rejects the request without; use
+// EncryptForQuery to build an indexed field's equality-comparison payload
+// instead.
+func (e *ExplicitEncryptor) Encrypt(ctx context.Context, keyAltName string, value interface{}, algorithm string) (primitive.Binary, error) {
+	keyID, err := e.lookupKeyID(ctx, keyAltName)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to look up data key %q: %w", keyAltName, err)
+	}
+
+	valueType, valueBytes, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to marshal value for encryption: %w", err)
+	}
+
+	return e.clientEncryption.Encrypt(ctx,
+		bson.RawValue{Type: valueType, Value: valueBytes},
+		options.Encrypt().SetAlgorithm(algorithm).SetKeyID(keyID),
+	)
+}
+
+// EncryptForQuery encrypts value under the DEK named keyAltName as an
+// equality-comparison payload for an Indexed Queryable Encryption field,
+// suitable for a raw aggregation pipeline's $match stage run outside the
+// auto-encryption path - unlike Encrypt, whose output can only be stored,
+// never compared, since Indexed encryption is randomized. contentionFactor
+// must match the contention the field's queries entry was created with
+// (DefaultContentionFactor for every field in GetEncryptedFieldsMap today).
+func (e *ExplicitEncryptor) EncryptForQuery(ctx context.Context, keyAltName string, value interface{}, contentionFactor int64) (primitive.Binary, error) {
+	keyID, err := e.lookupKeyID(ctx, keyAltName)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to look up data key %q: %w", keyAltName, err)
+	}
+
+	valueType, valueBytes, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to marshal value for encryption: %w", err)
+	}
+
+	return e.clientEncryption.Encrypt(ctx,
+		bson.RawValue{Type: valueType, Value: valueBytes},
+		options.Encrypt().
+			SetAlgorithm(AlgorithmIndexed).
+			SetKeyID(keyID).
+			SetQueryType("equality").
+			SetContentionFactor(contentionFactor),
+	)
+}
+
+// Decrypt reverses Encrypt, returning the plaintext BSON value. Use it to
+// read a Queryable Encryption field from a document that was fetched through
+// a client with no auto-encryption configured, e.g. a replication tool.
+func (e *ExplicitEncryptor) Decrypt(ctx context.Context, value primitive.Binary) (bson.RawValue, error) {
+	return e.clientEncryption.Decrypt(ctx, value)
+}
+
+func (e *ExplicitEncryptor) lookupKeyID(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	doc, err := e.clientEncryption.GetKeyByAltName(ctx, keyAltName)
+	if err != nil {
+		return primitive.Binary{}, err
+	}
+	if doc == nil {
+		return primitive.Binary{}, mongo.ErrNoDocuments
+	}
+
+	var key struct {
+		ID primitive.Binary `bson:"_id"`
+	}
+	if err := bson.Unmarshal(doc, &key); err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to decode data key %q: %w", keyAltName, err)
+	}
+	return key.ID, nil
+}
@@ -0,0 +1,246 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// KeyManager wraps mongo.ClientEncryption for the explicit-encryption paths
+// EncryptionManager's auto-encryption setup doesn't cover: named DEKs
+// addressed by keyAltName (e.g. "ssn-2024"), explicit Encrypt/Decrypt for
+// fields that need app-level control over which key and algorithm encrypt
+// them, and rewrapping every DEK under a new KMS master key during key
+// rotation.
+type KeyManager struct {
+	clientEncryption *mongo.ClientEncryption
+	keyVaultColl     *mongo.Collection
+	rotationLogColl  *mongo.Collection
+	provider         KMSProvider
+}
+
+// KeyRotationLog is an append-only record of one master-key rewrap run,
+// stored alongside the key vault so rotation history travels with the keys
+// it describes.
+type KeyRotationLog struct {
+	Provider    string    `bson:"provider"`
+	Operator    string    `bson:"operator"`
+	DocsTouched int64     `bson:"docsTouched"`
+	DryRun      bool      `bson:"dryRun"`
+	StartedAt   time.Time `bson:"startedAt"`
+	FinishedAt  time.Time `bson:"finishedAt,omitempty"`
+}
+
+// NewKeyManager builds a KeyManager backed by its own ClientEncryption,
+// talking to the same key vault namespace and KMS providers as em, using
+// client for key-vault reads/writes.
+func NewKeyManager(client *mongo.Client, keyVaultNamespace string, em *EncryptionManager) (*KeyManager, error) {
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(em.KmsProviders).
+		SetKeyVaultNamespace(keyVaultNamespace)
+
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client encryption: %w", err)
+	}
+
+	keyVaultDB, keyVaultCollection, err := splitNamespace(keyVaultNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KeyManager{
+		clientEncryption: clientEncryption,
+		keyVaultColl:     client.Database(keyVaultDB).Collection(keyVaultCollection),
+		rotationLogColl:  client.Database(keyVaultDB).Collection("__keyRotationLog"),
+		provider:         em.Provider,
+	}, nil
+}
+
+// Close releases the underlying ClientEncryption's resources.
+func (km *KeyManager) Close(ctx context.Context) error {
+	return km.clientEncryption.Close(ctx)
+}
+
+// CreateNamedDataKey creates a DEK tagged with keyAltName (e.g. "ssn-2024"),
+// or returns the existing one if a key with that alt name already exists -
+// callers don't need to track whether this is the first time a given name
+// has been used.
+func (km *KeyManager) CreateNamedDataKey(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	if keyID, err := km.lookupKeyID(ctx, keyAltName); err == nil {
+		return keyID, nil
+	} else if err != mongo.ErrNoDocuments {
+		return primitive.Binary{}, err
+	}
+
+	dataKeyOpts := options.DataKey().SetKeyAltNames([]string{keyAltName})
+	if masterKey := km.provider.MasterKey(); masterKey != nil {
+		dataKeyOpts.SetMasterKey(masterKey)
+	}
+	return km.clientEncryption.CreateDataKey(ctx, km.provider.Name(), dataKeyOpts)
+}
+
+// Encrypt explicitly encrypts value under the DEK named keyAltName using
+// algo (e.g. AEAD_AES_256_CBC_HMAC_SHA_512-Deterministic for an equality
+// query, -Random for a value that's never queried). This is for fields the
+// employees collection's auto-encryption/encryptedFieldsMap doesn't cover;
+// a field under auto-encryption never needs this, since the driver encrypts
+// it transparently.
+func (km *KeyManager) Encrypt(ctx context.Context, keyAltName string, value interface{}, algo string) (primitive.Binary, error) {
+	keyID, err := km.lookupKeyID(ctx, keyAltName)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to look up data key %q: %w", keyAltName, err)
+	}
+
+	valueType, valueBytes, err := bson.MarshalValue(value)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("unable to marshal value for encryption: %w", err)
+	}
+
+	return km.clientEncryption.Encrypt(ctx,
+		bson.RawValue{Type: valueType, Value: valueBytes},
+		options.Encrypt().SetAlgorithm(algo).SetKeyID(keyID),
+	)
+}
+
+// Decrypt reverses Encrypt, returning the plaintext BSON value.
+func (km *KeyManager) Decrypt(ctx context.Context, value primitive.Binary) (bson.RawValue, error) {
+	return km.clientEncryption.Decrypt(ctx, value)
+}
+
+// RotateMasterKey re-encrypts every DEK in the key vault under provider's
+// current master key via RewrapManyDataKey, and appends a KeyRotationLog
+// entry recording the run. Pass the same provider the app already uses to
+// rewrap in place after rotating a CMK's key material at the KMS (the usual
+// case); pass a different provider to move the entire key vault to a new
+// KMS. dryRun reports how many DEKs would be touched without rewrapping
+// anything.
+func (km *KeyManager) RotateMasterKey(ctx context.Context, provider KMSProvider, operator string, dryRun bool) (int64, error) {
+	logEntry := KeyRotationLog{
+		Provider:  provider.Name(),
+		Operator:  operator,
+		DryRun:    dryRun,
+		StartedAt: time.Now(),
+	}
+
+	if dryRun {
+		count, err := km.keyVaultColl.CountDocuments(ctx, bson.M{})
+		if err != nil {
+			return 0, err
+		}
+		logEntry.DocsTouched = count
+		logEntry.FinishedAt = time.Now()
+		return count, km.writeRotationLog(ctx, logEntry)
+	}
+
+	rewrapOpts := options.RewrapManyDataKey().SetProvider(provider.Name())
+	if masterKey := provider.MasterKey(); masterKey != nil {
+		rewrapOpts.SetMasterKey(masterKey)
+	}
+
+	result, err := km.clientEncryption.RewrapManyDataKey(ctx, bson.M{}, rewrapOpts)
+	if err != nil {
+		return 0, fmt.Errorf("unable to rewrap data keys: %w", err)
+	}
+
+	var touched int64
+	if result.BulkWriteResult != nil {
+		touched = result.BulkWriteResult.ModifiedCount
+	}
+
+	logEntry.DocsTouched = touched
+	logEntry.FinishedAt = time.Now()
+	if err := km.writeRotationLog(ctx, logEntry); err != nil {
+		return touched, err
+	}
+	return touched, nil
+}
+
+// GetDataKeyByAltName returns the key vault document for the DEK tagged with
+// keyAltName, or mongo.ErrNoDocuments if no key has that alt name.
+func (km *KeyManager) GetDataKeyByAltName(ctx context.Context, keyAltName string) (bson.Raw, error) {
+	doc, err := km.clientEncryption.GetKeyByAltName(ctx, keyAltName)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	return doc, nil
+}
+
+// ListDataKeys returns every DEK in the key vault, newest operations first
+// left to the caller (the key vault collection has no inherent order).
+func (km *KeyManager) ListDataKeys(ctx context.Context) ([]bson.Raw, error) {
+	cursor, err := km.clientEncryption.GetKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []bson.Raw
+	for cursor.Next(ctx) {
+		var doc bson.Raw
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		keys = append(keys, doc)
+	}
+	return keys, cursor.Err()
+}
+
+// DeleteDataKey removes a DEK from the key vault. Any field still encrypted
+// under it becomes unreadable, so callers must confirm nothing references id
+// before calling this - the key vault has no foreign-key check back to the
+// collections that used it.
+func (km *KeyManager) DeleteDataKey(ctx context.Context, id primitive.Binary) error {
+	_, err := km.clientEncryption.DeleteKey(ctx, id)
+	return err
+}
+
+// AddKeyAltName tags an existing DEK with an additional alternate name, so
+// explicit-encryption code can address it by a new keyAltName without
+// creating a second key for the same underlying data.
+func (km *KeyManager) AddKeyAltName(ctx context.Context, id primitive.Binary, keyAltName string) error {
+	_, err := km.clientEncryption.AddKeyAltName(ctx, id, keyAltName)
+	return err
+}
+
+// RemoveKeyAltName removes one alternate name from a DEK, leaving the key
+// itself and any of its other alt names intact.
+func (km *KeyManager) RemoveKeyAltName(ctx context.Context, id primitive.Binary, keyAltName string) error {
+	_, err := km.clientEncryption.RemoveKeyAltName(ctx, id, keyAltName)
+	return err
+}
+
+func (km *KeyManager) lookupKeyID(ctx context.Context, keyAltName string) (primitive.Binary, error) {
+	var doc bson.M
+	if err := km.keyVaultColl.FindOne(ctx, bson.M{"keyAltNames": keyAltName}).Decode(&doc); err != nil {
+		return primitive.Binary{}, err
+	}
+	id, ok := doc["_id"].(primitive.Binary)
+	if !ok {
+		return primitive.Binary{}, fmt.Errorf("key vault document for %q has no binary _id", keyAltName)
+	}
+	return id, nil
+}
+
+func (km *KeyManager) writeRotationLog(ctx context.Context, entry KeyRotationLog) error {
+	_, err := km.rotationLogColl.InsertOne(ctx, entry)
+	return err
+}
+
+// splitNamespace splits a "database.collection" namespace into its parts.
+func splitNamespace(namespace string) (db, collection string, err error) {
+	parts := strings.SplitN(namespace, ".", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid namespace %q: expected \"database.collection\"", namespace)
+	}
+	return parts[0], parts[1], nil
+}
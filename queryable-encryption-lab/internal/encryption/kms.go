@@ -0,0 +1,185 @@
+package encryption
+
+import (
+	"fmt"
+
+	"queryable-encryption-lab/internal/config"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// KMSProvider abstracts over the Queryable Encryption master key source so
+// EncryptionManager doesn't need to know whether it's talking to a local
+// file, a cloud KMS, or KMIP.
+type KMSProvider interface {
+	// Name is the KMS provider name MongoDB expects in kmsProviders, e.g. "aws".
+	Name() string
+	// Credentials is this provider's entry in the kmsProviders map passed to
+	// SetKmsProviders.
+	Credentials() map[string]interface{}
+	// MasterKey is the masterKey document passed to
+	// CreateEncryptedCollection/CreateDataKey for this provider. Local
+	// has none, since the local key itself is the only secret involved.
+	MasterKey() interface{}
+}
+
+// NewKMSProvider builds the KMSProvider selected by cfg.Provider, reading
+// localMasterKeyPath for the local provider. It returns an error describing
+// which environment variables are missing rather than connecting to
+// anything, so callers can fail fast at startup.
+func NewKMSProvider(cfg config.KMSConfig, localMasterKeyPath string) (KMSProvider, error) {
+	switch cfg.Provider {
+	case "", "local":
+		return newLocalKMSProvider(localMasterKeyPath)
+	case "aws":
+		return newAWSKMSProvider(cfg.AWS)
+	case "azure":
+		return newAzureKMSProvider(cfg.Azure)
+	case "gcp":
+		return newGCPKMSProvider(cfg.GCP)
+	case "kmip":
+		return newKMIPKMSProvider(cfg.KMIP)
+	default:
+		return nil, fmt.Errorf("unknown KMS_PROVIDER %q: use local, aws, azure, gcp, or kmip", cfg.Provider)
+	}
+}
+
+// localKMSProvider wraps a local, file-backed master key. It's meant for
+// development and demos; anything deployed should use a cloud KMS or KMIP
+// provider instead.
+type localKMSProvider struct {
+	key string
+}
+
+func newLocalKMSProvider(keyPath string) (*localKMSProvider, error) {
+	return &localKMSProvider{key: loadOrGenerateLocalMasterKey(keyPath)}, nil
+}
+
+func (p *localKMSProvider) Name() string { return "local" }
+
+func (p *localKMSProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{"key": p.key}
+}
+
+func (p *localKMSProvider) MasterKey() interface{} { return nil }
+
+// awsKMSProvider configures AWS KMS as the master key source.
+type awsKMSProvider struct {
+	cfg config.AWSKMSConfig
+}
+
+func newAWSKMSProvider(cfg config.AWSKMSConfig) (*awsKMSProvider, error) {
+	if cfg.Region == "" || cfg.KeyARN == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return nil, fmt.Errorf("KMS_PROVIDER=aws requires KMS_AWS_REGION, KMS_AWS_KEY_ARN, KMS_AWS_ACCESS_KEY_ID, and KMS_AWS_SECRET_ACCESS_KEY")
+	}
+	return &awsKMSProvider{cfg: cfg}, nil
+}
+
+func (p *awsKMSProvider) Name() string { return "aws" }
+
+func (p *awsKMSProvider) Credentials() map[string]interface{} {
+	creds := map[string]interface{}{
+		"accessKeyId":     p.cfg.AccessKeyID,
+		"secretAccessKey": p.cfg.SecretAccessKey,
+	}
+	if p.cfg.SessionToken != "" {
+		creds["sessionToken"] = p.cfg.SessionToken
+	}
+	return creds
+}
+
+func (p *awsKMSProvider) MasterKey() interface{} {
+	return bson.M{"region": p.cfg.Region, "key": p.cfg.KeyARN}
+}
+
+// azureKMSProvider configures Azure Key Vault as the master key source.
+type azureKMSProvider struct {
+	cfg config.AzureKMSConfig
+}
+
+func newAzureKMSProvider(cfg config.AzureKMSConfig) (*azureKMSProvider, error) {
+	if cfg.TenantID == "" || cfg.ClientID == "" || cfg.ClientSecret == "" || cfg.KeyVaultEndpoint == "" || cfg.KeyName == "" {
+		return nil, fmt.Errorf("KMS_PROVIDER=azure requires KMS_AZURE_TENANT_ID, KMS_AZURE_CLIENT_ID, KMS_AZURE_CLIENT_SECRET, KMS_AZURE_KEY_VAULT_ENDPOINT, and KMS_AZURE_KEY_NAME")
+	}
+	return &azureKMSProvider{cfg: cfg}, nil
+}
+
+func (p *azureKMSProvider) Name() string { return "azure" }
+
+func (p *azureKMSProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{
+		"tenantId":     p.cfg.TenantID,
+		"clientId":     p.cfg.ClientID,
+		"clientSecret": p.cfg.ClientSecret,
+	}
+}
+
+func (p *azureKMSProvider) MasterKey() interface{} {
+	masterKey := bson.M{
+		"keyVaultEndpoint": p.cfg.KeyVaultEndpoint,
+		"keyName":          p.cfg.KeyName,
+	}
+	if p.cfg.KeyVersion != "" {
+		masterKey["keyVersion"] = p.cfg.KeyVersion
+	}
+	return masterKey
+}
+
+// gcpKMSProvider configures GCP KMS as the master key source.
+type gcpKMSProvider struct {
+	cfg config.GCPKMSConfig
+}
+
+func newGCPKMSProvider(cfg config.GCPKMSConfig) (*gcpKMSProvider, error) {
+	if cfg.Email == "" || cfg.PrivateKey == "" || cfg.ProjectID == "" || cfg.Location == "" || cfg.KeyRing == "" || cfg.KeyName == "" {
+		return nil, fmt.Errorf("KMS_PROVIDER=gcp requires KMS_GCP_EMAIL, KMS_GCP_PRIVATE_KEY, KMS_GCP_PROJECT_ID, KMS_GCP_LOCATION, KMS_GCP_KEY_RING, and KMS_GCP_KEY_NAME")
+	}
+	return &gcpKMSProvider{cfg: cfg}, nil
+}
+
+func (p *gcpKMSProvider) Name() string { return "gcp" }
+
+func (p *gcpKMSProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{
+		"email":      p.cfg.Email,
+		"privateKey": p.cfg.PrivateKey,
+	}
+}
+
+func (p *gcpKMSProvider) MasterKey() interface{} {
+	masterKey := bson.M{
+		"projectId": p.cfg.ProjectID,
+		"location":  p.cfg.Location,
+		"keyRing":   p.cfg.KeyRing,
+		"keyName":   p.cfg.KeyName,
+	}
+	if p.cfg.KeyVersion != "" {
+		masterKey["keyVersion"] = p.cfg.KeyVersion
+	}
+	return masterKey
+}
+
+// kmipKMSProvider configures a KMIP server as the master key source.
+type kmipKMSProvider struct {
+	cfg config.KMIPKMSConfig
+}
+
+func newKMIPKMSProvider(cfg config.KMIPKMSConfig) (*kmipKMSProvider, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("KMS_PROVIDER=kmip requires KMS_KMIP_ENDPOINT")
+	}
+	return &kmipKMSProvider{cfg: cfg}, nil
+}
+
+func (p *kmipKMSProvider) Name() string { return "kmip" }
+
+func (p *kmipKMSProvider) Credentials() map[string]interface{} {
+	return map[string]interface{}{"endpoint": p.cfg.Endpoint}
+}
+
+func (p *kmipKMSProvider) MasterKey() interface{} {
+	if p.cfg.KeyID == "" {
+		return nil
+	}
+	return bson.M{"keyId": p.cfg.KeyID}
+}
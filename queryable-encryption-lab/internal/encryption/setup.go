@@ -9,6 +9,8 @@ import (
 	"log"
 	"os"
 
+	"queryable-encryption-lab/internal/config"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -22,27 +24,32 @@ const (
 // EncryptionManager handles encryption setup and key management
 type EncryptionManager struct {
 	KeyVaultNamespace  string
-	LocalMasterKeyPath string
+	Provider           KMSProvider
 	KmsProviders       map[string]map[string]interface{}
 	CryptSharedLibPath string
 }
 
-// NewEncryptionManager creates a new encryption manager
-func NewEncryptionManager(keyVaultNamespace, localMasterKeyPath, cryptSharedLibPath string) *EncryptionManager {
-	localMasterKey := loadOrGenerateLocalMasterKey(localMasterKeyPath)
+// NewEncryptionManager creates a new encryption manager, building the KMS
+// provider selected by encCfg.KMS.Provider. It returns an error instead of
+// exiting so callers can decide how to report a misconfigured provider; the
+// local provider still exits the process if its key file cannot be read or
+// written.
+func NewEncryptionManager(keyVaultNamespace string, encCfg config.EncryptionConfig) (*EncryptionManager, error) {
+	provider, err := NewKMSProvider(encCfg.KMS, encCfg.LocalMasterKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure KMS provider: %w", err)
+	}
 
 	kmsProviders := map[string]map[string]interface{}{
-		"local": {
-			"key": localMasterKey,
-		},
+		provider.Name(): provider.Credentials(),
 	}
 
 	return &EncryptionManager{
 		KeyVaultNamespace:  keyVaultNamespace,
-		LocalMasterKeyPath: localMasterKeyPath,
+		Provider:           provider,
 		KmsProviders:       kmsProviders,
-		CryptSharedLibPath: cryptSharedLibPath,
-	}
+		CryptSharedLibPath: encCfg.CryptSharedLibPath,
+	}, nil
 }
 
 // loadOrGenerateLocalMasterKey loads an existing master key or generates a new one
@@ -144,6 +151,66 @@ func GetEncryptedFieldsMap() bson.M {
 	}
 }
 
+// FieldKeyAltNames maps each encrypted field's path to the keyAltName
+// its DEK is tagged with under GetEncryptedFieldsMapWithKeys. Naming each
+// field's key up front is what lets later explicit-encryption code
+// (KeyManager.Encrypt, a targeted rewrap) address one field's key by name
+// instead of only ever touching the whole key vault at once.
+var FieldKeyAltNames = map[string]string{
+	"name":   "employees-name",
+	"ssn":    "employees-ssn",
+	"salary": "employees-salary",
+}
+
+// GetEncryptedFieldsMapWithKeys is GetEncryptedFieldsMap, but with each
+// field's keyId pre-assigned to a real DEK (tagged with the keyAltName from
+// FieldKeyAltNames) instead of left nil for CreateEncryptedCollection
+// to auto-generate anonymously.
+func (em *EncryptionManager) GetEncryptedFieldsMapWithKeys(ctx context.Context, client *mongo.Client) (bson.M, error) {
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(em.KmsProviders).
+		SetKeyVaultNamespace(em.KeyVaultNamespace)
+
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client encryption: %w", err)
+	}
+	defer func() {
+		if err := clientEncryption.Close(ctx); err != nil {
+			log.Printf("Error closing client encryption: %v", err)
+		}
+	}()
+
+	fieldsMap := GetEncryptedFieldsMap()
+	fields, ok := fieldsMap["fields"].([]bson.M)
+	if !ok {
+		return nil, fmt.Errorf("unexpected shape for encrypted fields map")
+	}
+
+	for i, field := range fields {
+		path, _ := field["path"].(string)
+		altName, ok := FieldKeyAltNames[path]
+		if !ok {
+			continue
+		}
+
+		dataKeyOpts := options.DataKey().SetKeyAltNames([]string{altName})
+		if masterKey := em.Provider.MasterKey(); masterKey != nil {
+			dataKeyOpts.SetMasterKey(masterKey)
+		}
+
+		keyID, err := clientEncryption.CreateDataKey(ctx, em.Provider.Name(), dataKeyOpts)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create data key for field %q: %w", path, err)
+		}
+
+		field["keyId"] = keyID
+		fields[i] = field
+	}
+
+	return bson.M{"fields": fields}, nil
+}
+
 // CreateEncryptedCollection creates the encrypted collection with proper configuration
 func (em *EncryptionManager) CreateEncryptedCollection(
 	ctx context.Context,
@@ -151,10 +218,26 @@ func (em *EncryptionManager) CreateEncryptedCollection(
 	database *mongo.Database,
 	collectionName string,
 ) error {
-	log.Println("Creating encrypted collection...")
+	encryptedFieldsMap, err := em.GetEncryptedFieldsMapWithKeys(ctx, client)
+	if err != nil {
+		return err
+	}
+	return em.CreateEncryptedCollectionWithFields(ctx, client, database, collectionName, encryptedFieldsMap)
+}
 
-	// Get encrypted fields map
-	encryptedFieldsMap := GetEncryptedFieldsMap()
+// CreateEncryptedCollectionWithFields is CreateEncryptedCollection with an
+// explicit encrypted fields map, rather than always GetEncryptedFieldsMap.
+// It exists for internal/migration, whose job is creating encrypted
+// collections under a fields map that has evolved since the one baked into
+// this package.
+func (em *EncryptionManager) CreateEncryptedCollectionWithFields(
+	ctx context.Context,
+	client *mongo.Client,
+	database *mongo.Database,
+	collectionName string,
+	encryptedFieldsMap bson.M,
+) error {
+	log.Println("Creating encrypted collection...")
 
 	// Create client encryption
 	clientEncryptionOpts := options.ClientEncryption().
@@ -180,8 +263,8 @@ func (em *EncryptionManager) CreateEncryptedCollection(
 		database,
 		collectionName,
 		createCollectionOpts,
-		"local", // KMS provider name
-		nil,     // masterKey (not needed for local provider)
+		em.Provider.Name(),
+		em.Provider.MasterKey(),
 	)
 	if err != nil {
 		return fmt.Errorf("unable to create encrypted collection: %w", err)
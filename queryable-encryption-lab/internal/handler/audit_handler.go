@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"queryable-encryption-lab/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler serves the admin audit-log endpoint.
+type AuditHandler struct {
+	auditor *audit.MongoAuditor
+}
+
+// NewAuditHandler creates a new handler instance.
+func NewAuditHandler(auditor *audit.MongoAuditor) *AuditHandler {
+	return &AuditHandler{auditor: auditor}
+}
+
+// GetAuditLog returns audit entries, optionally filtered by doer, resource,
+// and time window.
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	filter := audit.ListFilter{
+		DoerID:   c.Query("doer"),
+		Resource: c.Query("resource"),
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid 'from' timestamp: " + err.Error()})
+			return
+		}
+		filter.From = from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid 'to' timestamp: " + err.Error()})
+			return
+		}
+		filter.To = to
+	}
+
+	entries, err := h.auditor.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"net/http"
+
+	"queryable-encryption-lab/internal/encryption"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// KeyHandler exposes encryption.KeyManager's rotation over HTTP so an
+// operator can rewrap the key vault without redeploying.
+type KeyHandler struct {
+	keyMgr   *encryption.KeyManager
+	provider encryption.KMSProvider
+}
+
+// NewKeyHandler creates a new key handler instance. provider is the KMS
+// provider rotation rewraps to - normally the app's currently configured
+// provider, since the usual rotation is "CMK material changed at the KMS,
+// rewrap DEKs to match."
+func NewKeyHandler(keyMgr *encryption.KeyManager, provider encryption.KMSProvider) *KeyHandler {
+	return &KeyHandler{keyMgr: keyMgr, provider: provider}
+}
+
+// RotateKeys godoc
+// @Summary Rewrap every data encryption key under the current master key
+// @Description Walks the key vault via RewrapManyDataKey, recording the run to the key rotation log
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body rotateKeysRequest false "Rotation options"
+// @Success 200 {object} rotateKeysResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/keys/rotate [post]
+func (h *KeyHandler) RotateKeys(c *gin.Context) {
+	var req rotateKeysRequest
+	// Body is optional: an operator hitting this with curl -X POST and no
+	// body should still get a real rotation, not a 400.
+	_ = c.ShouldBindJSON(&req)
+
+	operator := req.Operator
+	if operator == "" {
+		operator = "api"
+	}
+
+	touched, err := h.keyMgr.RotateMasterKey(c.Request.Context(), h.provider, operator, req.DryRun)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error rotating keys: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rotateKeysResponse{Rewrapped: touched, DryRun: req.DryRun})
+}
+
+type rotateKeysRequest struct {
+	Operator string `json:"operator"`
+	DryRun   bool   `json:"dryRun"`
+}
+
+type rotateKeysResponse struct {
+	Rewrapped int64 `json:"rewrapped"`
+	DryRun    bool  `json:"dryRun"`
+}
+
+// ListDataKeys godoc
+// @Summary List every data encryption key in the key vault
+// @Tags admin
+// @Produce json
+// @Success 200 {array} bson.Raw
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/keys [get]
+func (h *KeyHandler) ListDataKeys(c *gin.Context) {
+	keys, err := h.keyMgr.ListDataKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error listing data keys: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, keys)
+}
+
+// GetDataKey godoc
+// @Summary Get a data encryption key by its keyAltName
+// @Tags admin
+// @Produce json
+// @Param altName path string true "Key alt name"
+// @Success 200 {object} bson.Raw
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/keys/{altName} [get]
+func (h *KeyHandler) GetDataKey(c *gin.Context) {
+	doc, err := h.keyMgr.GetDataKeyByAltName(c.Request.Context(), c.Param("altName"))
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "no data key named " + c.Param("altName")})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching data key: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// DeleteDataKey godoc
+// @Summary Delete a data encryption key by its keyAltName
+// @Tags admin
+// @Produce json
+// @Param altName path string true "Key alt name"
+// @Success 200 {object} SuccessResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/keys/{altName} [delete]
+func (h *KeyHandler) DeleteDataKey(c *gin.Context) {
+	altName := c.Param("altName")
+
+	doc, err := h.keyMgr.GetDataKeyByAltName(c.Request.Context(), altName)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{Error: "no data key named " + altName})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching data key: " + err.Error()})
+		return
+	}
+
+	var key struct {
+		ID primitive.Binary `bson:"_id"`
+	}
+	if err := bson.Unmarshal(doc, &key); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error decoding data key: " + err.Error()})
+		return
+	}
+
+	if err := h.keyMgr.DeleteDataKey(c.Request.Context(), key.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error deleting data key: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{Message: "data key deleted"})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"queryable-encryption-lab/internal/audit"
+)
+
+const (
+	doerIDHeader  = "X-User-ID"
+	traceIDHeader = "X-Trace-ID"
+	adminHeader   = "X-Admin"
+	anonymousDoer = "anonymous"
+)
+
+// AuthContext extracts the acting principal from the X-User-ID header (a
+// stand-in for a real JWT claim) and an optional X-Trace-ID, stashing both
+// on the request context so downstream services can attribute audit
+// entries without taking a dependency on gin.
+func AuthContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		doerID := c.GetHeader(doerIDHeader)
+		if doerID == "" {
+			doerID = anonymousDoer
+		}
+
+		ctx := audit.WithDoerID(c.Request.Context(), doerID)
+		if traceID := c.GetHeader(traceIDHeader); traceID != "" {
+			ctx = audit.WithTraceID(ctx, traceID)
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Set("doerID", doerID)
+		c.Next()
+	}
+}
+
+// RequireAdmin gates a route behind the X-Admin header until real role
+// checks land. It's intentionally minimal: enough to keep the audit log off
+// the public surface without building out a full authz model.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(adminHeader) != "true" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,120 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	"queryable-encryption-lab/internal/encryption"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RecreateWithFields changes a collection's encrypted fields map. Queryable
+// Encryption fields are fixed at collection creation time, so there's no
+// in-place equivalent of collMod for them: this creates newName as a fresh
+// encrypted collection under newFields, copies every document across (the
+// driver's auto-encryption transparently decrypts reads from oldName and
+// re-encrypts writes to newName under whatever keys/algorithms newFields
+// specifies), and atomically renames newName over oldName. Callers normally
+// run this inside a Migration's Up.
+func RecreateWithFields(
+	ctx context.Context,
+	client *mongo.Client,
+	db *mongo.Database,
+	encMgr *encryption.EncryptionManager,
+	oldName, newName string,
+	newFields bson.M,
+) error {
+	if err := encMgr.CreateEncryptedCollectionWithFields(ctx, client, db, newName, newFields); err != nil {
+		return fmt.Errorf("unable to create %q with new encrypted fields: %w", newName, err)
+	}
+
+	if err := copyDocuments(ctx, db, oldName, newName); err != nil {
+		return fmt.Errorf("unable to copy %q into %q: %w", oldName, newName, err)
+	}
+
+	return AtomicRename(ctx, client, db, newName, oldName)
+}
+
+// copyDocuments streams every document in oldName through the encrypted
+// client into newName. It goes through a plain Find/InsertMany rather than
+// an aggregation $out, since $out can't run through mongocryptd/auto
+// encryption the way normal CRUD commands can.
+func copyDocuments(ctx context.Context, db *mongo.Database, oldName, newName string) error {
+	const batchSize = 500
+
+	cursor, err := db.Collection(oldName).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	batch := make([]interface{}, 0, batchSize)
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		batch = append(batch, doc)
+
+		if len(batch) == batchSize {
+			if _, err := db.Collection(newName).InsertMany(ctx, batch); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(batch) > 0 {
+		if _, err := db.Collection(newName).InsertMany(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AtomicRename renames src over dst using the renameCollection admin
+// command with dropTarget set, so the old collection disappears and the new
+// one takes its name in a single atomic operation rather than a
+// drop-then-rename window where dst briefly doesn't exist.
+func AtomicRename(ctx context.Context, client *mongo.Client, db *mongo.Database, src, dst string) error {
+	cmd := bson.D{
+		{Key: "renameCollection", Value: db.Name() + "." + src},
+		{Key: "to", Value: db.Name() + "." + dst},
+		{Key: "dropTarget", Value: true},
+	}
+	return client.Database("admin").RunCommand(ctx, cmd).Err()
+}
+
+// CreateDataKeyAndField creates a new data encryption key via
+// ClientEncryption.CreateDataKey and returns a field entry ready to append
+// to an encrypted fields map's "fields" array, so a migration that adds a
+// field doesn't have to repeat the ClientEncryption plumbing
+// CreateEncryptedCollection already does internally.
+func CreateDataKeyAndField(ctx context.Context, client *mongo.Client, encMgr *encryption.EncryptionManager, path, bsonType string, queries []bson.M) (bson.M, error) {
+	clientEncryptionOpts := options.ClientEncryption().
+		SetKmsProviders(encMgr.KmsProviders).
+		SetKeyVaultNamespace(encMgr.KeyVaultNamespace)
+
+	clientEncryption, err := mongo.NewClientEncryption(client, clientEncryptionOpts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client encryption: %w", err)
+	}
+	defer clientEncryption.Close(ctx)
+
+	keyID, err := clientEncryption.CreateDataKey(ctx, encMgr.Provider.Name(), options.DataKey())
+	if err != nil {
+		return nil, fmt.Errorf("unable to create data key: %w", err)
+	}
+
+	return bson.M{
+		"keyId":    keyID,
+		"path":     path,
+		"bsonType": bsonType,
+		"queries":  queries,
+	}, nil
+}
@@ -0,0 +1,44 @@
+// Package migration runs versioned schema migrations for the encrypted
+// employees collection at startup. setupEncryptedCollection only ever
+// creates the collection once; anything that needs to change afterwards -
+// adding an encrypted field, widening a query type, reshaping a document -
+// has to go through a migration, since Queryable Encryption's encrypted
+// fields map is immutable once a collection exists.
+package migration
+
+import (
+	"context"
+
+	"queryable-encryption-lab/internal/encryption"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// UpFunc applies one migration. It receives the same client/db/encMgr
+// triple CreateEncryptedCollection does, so migrations can create data
+// keys, read and rewrite documents, and create or rename collections.
+type UpFunc func(ctx context.Context, client *mongo.Client, db *mongo.Database, encMgr *encryption.EncryptionManager) error
+
+// Migration is one registered schema change. Version must be unique and
+// migrations run in ascending Version order.
+type Migration struct {
+	Version     int
+	Description string
+	Up          UpFunc
+}
+
+// registry holds every migration registered via Register, in registration
+// order. Runner sorts it by Version before applying.
+var registry []Migration
+
+// Register adds a migration to the package-level registry. It's meant to be
+// called from an init() in a migrations package imported for side effects
+// (see internal/migrations), the same way database/sql drivers register
+// themselves.
+func Register(version int, description string, up UpFunc) {
+	registry = append(registry, Migration{
+		Version:     version,
+		Description: description,
+		Up:          up,
+	})
+}
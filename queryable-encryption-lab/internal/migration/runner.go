@@ -0,0 +1,206 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"queryable-encryption-lab/internal/encryption"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// collectionName is where applied-migration records and the lock
+	// document both live.
+	collectionName = "__migrations"
+	lockID         = "migration-lock"
+	lockTTL        = 5 * time.Minute
+)
+
+// record is an applied migration, persisted with Version as _id so a
+// second application of the same version is rejected by the unique index
+// instead of silently re-running.
+type record struct {
+	Version   int       `bson:"_id"`
+	Checksum  string    `bson:"checksum"`
+	AppliedAt time.Time `bson:"appliedAt"`
+}
+
+// lockDoc guards concurrent runs across replicas booting at the same time.
+// ExpiresAt carries a TTL index so a replica that dies mid-migration doesn't
+// wedge every other replica behind a lock that will never be released.
+type lockDoc struct {
+	ID         string    `bson:"_id"`
+	Holder     string    `bson:"holder"`
+	AcquiredAt time.Time `bson:"acquiredAt"`
+	ExpiresAt  time.Time `bson:"expiresAt"`
+}
+
+// Runner applies pending migrations from the package registry against a
+// single database.
+type Runner struct {
+	client *mongo.Client
+	db     *mongo.Database
+	encMgr *encryption.EncryptionManager
+	coll   *mongo.Collection
+}
+
+// NewRunner builds a Runner that tracks state in db's __migrations
+// collection and passes client/db/encMgr through to each migration's Up.
+func NewRunner(client *mongo.Client, db *mongo.Database, encMgr *encryption.EncryptionManager) *Runner {
+	return &Runner{
+		client: client,
+		db:     db,
+		encMgr: encMgr,
+		coll:   db.Collection(collectionName),
+	}
+}
+
+// Run applies every registered migration whose Version hasn't been recorded
+// as applied yet, in ascending Version order, holding the migration lock for
+// the duration. It's safe to call concurrently from multiple replicas
+// booting at once: only one will win the lock, and the rest fail fast with
+// an error the caller can log and retry on next boot.
+func (r *Runner) Run(ctx context.Context) error {
+	if err := r.ensureLockIndex(ctx); err != nil {
+		return fmt.Errorf("unable to create migration lock index: %w", err)
+	}
+
+	release, err := r.acquireLock(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	pending, err := r.pending(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(pending) == 0 {
+		log.Println("No pending migrations")
+		return nil
+	}
+
+	for _, m := range pending {
+		log.Printf("Applying migration %d: %s", m.Version, m.Description)
+		if err := m.Up(ctx, r.client, r.db, r.encMgr); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Description, err)
+		}
+		if err := r.recordApplied(ctx, m); err != nil {
+			return fmt.Errorf("migration %d (%s) succeeded but failed to record: %w", m.Version, m.Description, err)
+		}
+	}
+
+	return nil
+}
+
+// pending returns the registered migrations that haven't been applied yet,
+// sorted by Version. It also catches drift: if an already-applied version's
+// registered Description no longer matches its stored checksum, something
+// changed the migration after it ran, and re-running it with a different
+// body than what was recorded would be unsafe.
+func (r *Runner) pending(ctx context.Context) ([]Migration, error) {
+	sorted := make([]Migration, len(registry))
+	copy(sorted, registry)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	applied := map[int]record{}
+	cursor, err := r.coll.Find(ctx, bson.M{"_id": bson.M{"$ne": lockID}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var recs []record
+	if err := cursor.All(ctx, &recs); err != nil {
+		return nil, fmt.Errorf("unable to decode applied migrations: %w", err)
+	}
+	for _, rec := range recs {
+		applied[rec.Version] = rec
+	}
+
+	var pending []Migration
+	for _, m := range sorted {
+		rec, ok := applied[m.Version]
+		if !ok {
+			pending = append(pending, m)
+			continue
+		}
+		if rec.Checksum != checksum(m) {
+			return nil, fmt.Errorf("migration %d (%s) was applied with a different body than is currently registered", m.Version, m.Description)
+		}
+	}
+	return pending, nil
+}
+
+func (r *Runner) recordApplied(ctx context.Context, m Migration) error {
+	_, err := r.coll.InsertOne(ctx, record{
+		Version:   m.Version,
+		Checksum:  checksum(m),
+		AppliedAt: time.Now(),
+	})
+	return err
+}
+
+// checksum is a fingerprint of the migration's declared identity, not its Go
+// source: funcs can't be hashed at runtime. It exists to catch a registered
+// migration's Version being reused for a different Description, which is
+// the symptom of someone editing a migration after it already ran.
+func checksum(m Migration) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s", m.Version, m.Description)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *Runner) ensureLockIndex(ctx context.Context) error {
+	_, err := r.coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0).SetSparse(true),
+	})
+	return err
+}
+
+// acquireLock inserts the lock document, relying on the collection's unique
+// _id index to fail a second, concurrent insert. It returns a release func
+// the caller must defer; if the process dies before calling it, the TTL
+// index reclaims the lock after lockTTL instead of wedging future boots.
+func (r *Runner) acquireLock(ctx context.Context) (func(), error) {
+	holder := lockHolder()
+	now := time.Now()
+	_, err := r.coll.InsertOne(ctx, lockDoc{
+		ID:         lockID,
+		Holder:     holder,
+		AcquiredAt: now,
+		ExpiresAt:  now.Add(lockTTL),
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("migration lock is held by another replica (expires automatically if stale)")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to acquire migration lock: %w", err)
+	}
+
+	return func() {
+		releaseCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, err := r.coll.DeleteOne(releaseCtx, bson.M{"_id": lockID, "holder": holder}); err != nil {
+			log.Printf("Error releasing migration lock: %v", err)
+		}
+	}, nil
+}
+
+func lockHolder() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown-host"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
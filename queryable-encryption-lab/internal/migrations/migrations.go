@@ -0,0 +1,28 @@
+// Package migrations registers this application's schema migrations with
+// internal/migration. It's imported from cmd/api/main.go for its side
+// effects only (an init() per migration); the actual execution order and
+// bookkeeping live in migration.Runner.
+package migrations
+
+import (
+	"context"
+
+	"queryable-encryption-lab/internal/encryption"
+	"queryable-encryption-lab/internal/migration"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func init() {
+	migration.Register(1, "baseline employees encrypted fields (name/ssn equality, salary range)", baseline)
+}
+
+// baseline is a no-op. setupEncryptedCollection already creates the
+// employees collection with encryption.GetEncryptedFieldsMapWithKeys before the
+// migration runner ever runs, so there's nothing left to apply here -
+// registering it gives later migrations (which do change the encrypted
+// fields map) a known version 1 to build on instead of an untracked
+// starting point.
+func baseline(ctx context.Context, client *mongo.Client, db *mongo.Database, encMgr *encryption.EncryptionManager) error {
+	return nil
+}
@@ -6,9 +6,12 @@ import (
 	"fmt"
 	"log"
 
+	"queryable-encryption-lab/internal/audit"
+	"queryable-encryption-lab/internal/encryption"
 	"queryable-encryption-lab/internal/models"
 	"queryable-encryption-lab/internal/repository"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -24,6 +27,8 @@ type EmployeeService interface {
 	UpdateEmployee(ctx context.Context, id string, req *models.UpdateEmployeeRequest) (*models.Employee, error)
 	DeleteEmployee(ctx context.Context, id string) error
 	GetStats(ctx context.Context) (*EmployeeStats, error)
+	EncryptSSNForLookup(ctx context.Context, ssn string) (primitive.Binary, error)
+	Decrypt(ctx context.Context, value primitive.Binary) (bson.RawValue, error)
 }
 
 // EmployeeStats contains employee statistics
@@ -32,19 +37,76 @@ type EmployeeStats struct {
 }
 
 type employeeService struct {
-	repo repository.EmployeeRepository
+	repo              repository.EmployeeRepository
+	auditor           audit.Auditor                 // optional: records Create/Update/Delete to the audit log
+	explicitEncryptor *encryption.ExplicitEncryptor // optional: backs EncryptSSNForLookup/Decrypt
+}
+
+// EmployeeServiceOption configures optional dependencies on NewEmployeeService.
+type EmployeeServiceOption func(*employeeService)
+
+// WithAuditor wires an Auditor so Create/Update/Delete record a before/after
+// entry to the audit log. It is optional: without it, mutations proceed
+// unaudited.
+func WithAuditor(auditor audit.Auditor) EmployeeServiceOption {
+	return func(s *employeeService) {
+		s.auditor = auditor
+	}
+}
+
+// WithExplicitEncryptor wires an ExplicitEncryptor so EncryptSSNForLookup and
+// Decrypt become available to callers building raw aggregation pipelines or
+// reading documents fetched outside the auto-encryption path. It is
+// optional: without it, those two methods return an error.
+func WithExplicitEncryptor(encryptor *encryption.ExplicitEncryptor) EmployeeServiceOption {
+	return func(s *employeeService) {
+		s.explicitEncryptor = encryptor
+	}
 }
 
 // NewEmployeeService creates a new employee service
-func NewEmployeeService(repo repository.EmployeeRepository) EmployeeService {
-	return &employeeService{
-		repo: repo,
+func NewEmployeeService(repo repository.EmployeeRepository, opts ...EmployeeServiceOption) EmployeeService {
+	s := &employeeService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// recordAudit writes a before/after diff to the audit log. Failures are
+// logged rather than propagated: auditing must never block the employee
+// endpoints it's observing.
+func (s *employeeService) recordAudit(ctx context.Context, action, resourceID string, before, after any) {
+	if s.auditor == nil {
+		return
+	}
+
+	beforeDiff, afterDiff, err := audit.Diff(before, after)
+	if err != nil {
+		log.Printf("Error diffing employee %s for audit: %v", resourceID, err)
+		return
+	}
+
+	entry := audit.Entry{
+		Action:     action,
+		Resource:   "employee",
+		ResourceID: resourceID,
+		DoerID:     audit.DoerID(ctx),
+		Before:     beforeDiff,
+		After:      afterDiff,
+		RequestID:  audit.TraceID(ctx),
+	}
+	if err := s.auditor.Record(ctx, entry); err != nil {
+		log.Printf("Error recording audit entry for employee %s: %v", resourceID, err)
 	}
 }
 
 // CreateEmployee creates a new employee
 func (s *employeeService) CreateEmployee(ctx context.Context, req *models.CreateEmployeeRequest) (*models.Employee, error) {
 	employee := req.ToEmployee()
+	employee.DoerID = audit.DoerID(ctx)
+	employee.OwnerID = employee.DoerID
+	employee.TraceID = audit.TraceID(ctx)
 
 	if err := employee.Validate(); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
@@ -55,6 +117,7 @@ func (s *employeeService) CreateEmployee(ctx context.Context, req *models.Create
 	}
 
 	log.Printf("Employee created successfully: %s", employee.SanitizedEmployee().Name)
+	s.recordAudit(ctx, "create", employee.ID.Hex(), nil, employee)
 	return employee, nil
 }
 
@@ -153,7 +216,10 @@ func (s *employeeService) UpdateEmployee(ctx context.Context, id string, req *mo
 		return nil, err
 	}
 
+	before := *employee
 	req.ApplyUpdates(employee)
+	employee.DoerID = audit.DoerID(ctx)
+	employee.TraceID = audit.TraceID(ctx)
 
 	if err := employee.Validate(); err != nil {
 		return nil, fmt.Errorf("validation error: %w", err)
@@ -164,6 +230,7 @@ func (s *employeeService) UpdateEmployee(ctx context.Context, id string, req *mo
 	}
 
 	log.Printf("Employee updated successfully: %s", employee.SanitizedEmployee().Name)
+	s.recordAudit(ctx, "update", employee.ID.Hex(), &before, employee)
 	return employee, nil
 }
 
@@ -174,12 +241,18 @@ func (s *employeeService) DeleteEmployee(ctx context.Context, id string) error {
 		return fmt.Errorf("invalid employee ID format: %w", err)
 	}
 
+	employee, err := s.repo.FindByID(ctx, objectID)
+	if err != nil {
+		return err
+	}
+
 	if err := s.repo.Delete(ctx, objectID); err != nil {
 		log.Printf("Error deleting employee: %v", err)
 		return err
 	}
 
 	log.Printf("Employee deleted successfully: ID %s", id)
+	s.recordAudit(ctx, "delete", employee.ID.Hex(), employee, nil)
 	return nil
 }
 
@@ -194,3 +267,36 @@ func (s *employeeService) GetStats(ctx context.Context) (*EmployeeStats, error)
 		TotalEmployees: count,
 	}, nil
 }
+
+// EncryptSSNForLookup encrypts ssn under the same DEK as the employees
+// collection's auto-encryption for the ssn field, as an equality-comparison
+// payload producing a primitive.Binary a caller can drop straight into a
+// raw aggregation pipeline's $match stage - useful for callers that run
+// outside the auto-encryption client, e.g. an analytics job reading a
+// replica.
+func (s *employeeService) EncryptSSNForLookup(ctx context.Context, ssn string) (primitive.Binary, error) {
+	if s.explicitEncryptor == nil {
+		return primitive.Binary{}, fmt.Errorf("explicit encryption is not configured")
+	}
+
+	bin, err := s.explicitEncryptor.EncryptForQuery(ctx, encryption.FieldKeyAltNames["ssn"], ssn, encryption.DefaultContentionFactor)
+	if err != nil {
+		return primitive.Binary{}, fmt.Errorf("error encrypting ssn for lookup: %w", err)
+	}
+	return bin, nil
+}
+
+// Decrypt reverses EncryptSSNForLookup, or decrypts any other Queryable
+// Encryption value read from a document fetched through a client with no
+// auto-encryption configured, e.g. a replication tool.
+func (s *employeeService) Decrypt(ctx context.Context, value primitive.Binary) (bson.RawValue, error) {
+	if s.explicitEncryptor == nil {
+		return bson.RawValue{}, fmt.Errorf("explicit encryption is not configured")
+	}
+
+	raw, err := s.explicitEncryptor.Decrypt(ctx, value)
+	if err != nil {
+		return bson.RawValue{}, fmt.Errorf("error decrypting value: %w", err)
+	}
+	return raw, nil
+}
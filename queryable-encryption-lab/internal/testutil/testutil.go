@@ -0,0 +1,135 @@
+// Package testutil provides integration-test lifecycle helpers: session
+// cleanup and Queryable Encryption state teardown between runs, so tests
+// added against this package's collections start from a clean slate instead
+// of tripping over a DEK or esc/ecoc collection a previous run left behind.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+
+	"queryable-encryption-lab/internal/encryption"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TerminateOpenSessions runs killAllSessions against admin, mirroring the
+// driver's unified-spec-test runner's terminateOpenSessions step: it forces
+// closed any session a previous test run left open (e.g. a killed process),
+// so the next run doesn't stall waiting on it. Servers older than 3.6 don't
+// support sessions, so this is a no-op there; an Interrupted (code 11601)
+// response means another client raced to kill the same sessions, which is
+// also fine.
+func TerminateOpenSessions(ctx context.Context, client *mongo.Client) error {
+	major, minor, err := serverVersion(ctx, client)
+	if err != nil {
+		return fmt.Errorf("error checking server version: %w", err)
+	}
+	if major < 3 || (major == 3 && minor < 6) {
+		return nil
+	}
+
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "killAllSessions", Value: bson.A{}}}).Err()
+	if err == nil {
+		return nil
+	}
+
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 11601 {
+		return nil
+	}
+	return fmt.Errorf("error killing open sessions: %w", err)
+}
+
+// ResetEncryptedCollection drops the encrypted collection name in db along
+// with the enxcol_.<name>.esc and enxcol_.<name>.ecoc state collections
+// Queryable Encryption maintains alongside it, and removes any key vault
+// entry tagged for that collection (FieldKeyAltNames' "<name>-<field>"
+// convention) from keyVaultColl. Without this, a DEK or esc/ecoc state from
+// one test run lingers for the next and CreateEncryptedCollection rejects
+// recreating the collection.
+func ResetEncryptedCollection(ctx context.Context, db *mongo.Database, keyVaultColl *mongo.Collection, name string) error {
+	for _, coll := range []string{name, "enxcol_." + name + ".esc", "enxcol_." + name + ".ecoc"} {
+		if err := encryption.DropCollectionIfExists(ctx, db, coll); err != nil {
+			return fmt.Errorf("error dropping %s: %w", coll, err)
+		}
+	}
+
+	if keyVaultColl == nil {
+		return nil
+	}
+	filter := bson.M{"keyAltNames": bson.M{"$regex": "^" + name + "-"}}
+	if _, err := keyVaultColl.DeleteMany(ctx, filter); err != nil {
+		return fmt.Errorf("error deleting key vault entries for %s: %w", name, err)
+	}
+	return nil
+}
+
+// Main is a TestMain template. A package's integration tests wire it in as:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(testutil.Main(m, connect, db, keyVaultColl, "employees"))
+//	}
+//
+// connect returns the shared mongo.Client integration tests run against.
+// Main terminates any sessions a previous run left open before tests start,
+// runs m.Run(), then resets every named collection (dropping its esc/ecoc
+// sidecars and key vault entries) so the next run starts clean regardless of
+// whether this one passed.
+func Main(m *testing.M, connect func() (*mongo.Client, error), db *mongo.Database, keyVaultColl *mongo.Collection, collections ...string) int {
+	ctx := context.Background()
+
+	client, err := connect()
+	if err != nil {
+		log.Fatalf("testutil: error connecting to MongoDB: %v", err)
+	}
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("testutil: error disconnecting: %v", err)
+		}
+	}()
+
+	if err := TerminateOpenSessions(ctx, client); err != nil {
+		log.Fatalf("testutil: error terminating open sessions: %v", err)
+	}
+
+	code := m.Run()
+
+	for _, name := range collections {
+		if err := ResetEncryptedCollection(ctx, db, keyVaultColl, name); err != nil {
+			log.Printf("testutil: error resetting %s: %v", name, err)
+		}
+	}
+
+	return code
+}
+
+// serverVersion returns the connected server's major.minor version from
+// buildInfo, the same command mongosh and the driver's internal test runner
+// use to gate version-dependent behavior.
+func serverVersion(ctx context.Context, client *mongo.Client) (major, minor int, err error) {
+	var buildInfo struct {
+		Version string `bson:"version"`
+	}
+	if err := client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&buildInfo); err != nil {
+		return 0, 0, err
+	}
+
+	parts := strings.SplitN(buildInfo.Version, ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, fmt.Errorf("unexpected server version format %q", buildInfo.Version)
+	}
+	if major, err = strconv.Atoi(parts[0]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected server version format %q: %w", buildInfo.Version, err)
+	}
+	if minor, err = strconv.Atoi(parts[1]); err != nil {
+		return 0, 0, fmt.Errorf("unexpected server version format %q: %w", buildInfo.Version, err)
+	}
+	return major, minor, nil
+}
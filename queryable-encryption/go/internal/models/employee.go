@@ -21,13 +21,13 @@ const (
 
 // Position constants
 const (
-	PosSoftwareEngineer   = "Software Engineer"
-	PosSeniorEngineer     = "Senior Software Engineer"
-	PosProductManager     = "Product Manager"
-	PosHRSpecialist       = "HR Specialist"
-	PosMarketingManager   = "Marketing Manager"
-	PosSalesRep           = "Sales Representative"
-	PosFinancialAnalyst   = "Financial Analyst"
+	PosSoftwareEngineer = "Software Engineer"
+	PosSeniorEngineer   = "Senior Software Engineer"
+	PosProductManager   = "Product Manager"
+	PosHRSpecialist     = "HR Specialist"
+	PosMarketingManager = "Marketing Manager"
+	PosSalesRep         = "Sales Representative"
+	PosFinancialAnalyst = "Financial Analyst"
 )
 
 // Employee represents an employee document in the database.
@@ -55,6 +55,15 @@ type Employee struct {
 	// Metadata
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+
+	// Audit trail, mirroring todo-crud-api's Task: DoerID is whoever performed
+	// the most recent mutation, OwnerID is fixed at creation, and TraceID
+	// correlates this document's state with its audit_log entries. No code in
+	// this module sets these fields; they stay empty unless the document was
+	// written with them by another writer.
+	DoerID  string `json:"doerId,omitempty" bson:"doerId,omitempty"`
+	OwnerID string `json:"ownerId,omitempty" bson:"ownerId,omitempty"`
+	TraceID string `json:"traceId,omitempty" bson:"traceId,omitempty"`
 }
 
 // CreateEmployeeRequest represents the payload to create a new employee
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"runtime"
 
 	"queryable-encryption-lab/internal/models"
 
@@ -32,18 +33,43 @@ type EmployeeRepository interface {
 	Update(ctx context.Context, id primitive.ObjectID, employee *models.Employee) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	Count(ctx context.Context) (int64, error)
-	SeedEmployees(ctx context.Context, count int) error
+	SeedEmployees(ctx context.Context, count int) (*SeedReport, error)
 }
 
 type employeeRepository struct {
-	collection *mongo.Collection
+	collection  *mongo.Collection
+	seedWorkers int
+	seedBatch   int
+}
+
+// EmployeeRepositoryOption configures optional settings on NewEmployeeRepository.
+type EmployeeRepositoryOption func(*employeeRepository)
+
+// WithSeedConcurrency overrides the worker count and batch size used by
+// SeedEmployees. Callers that don't need tuning can omit this option and get
+// the defaults (runtime.NumCPU() workers, batches of 500).
+func WithSeedConcurrency(workers, batchSize int) EmployeeRepositoryOption {
+	return func(r *employeeRepository) {
+		if workers > 0 {
+			r.seedWorkers = workers
+		}
+		if batchSize > 0 {
+			r.seedBatch = batchSize
+		}
+	}
 }
 
 // NewEmployeeRepository creates a new employee repository
-func NewEmployeeRepository(db *mongo.Database, collectionName string) EmployeeRepository {
-	return &employeeRepository{
-		collection: db.Collection(collectionName),
+func NewEmployeeRepository(db *mongo.Database, collectionName string, opts ...EmployeeRepositoryOption) EmployeeRepository {
+	r := &employeeRepository{
+		collection:  db.Collection(collectionName),
+		seedWorkers: runtime.NumCPU(),
+		seedBatch:   defaultSeedBatchSize,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
 }
 
 // Create inserts a new employee document
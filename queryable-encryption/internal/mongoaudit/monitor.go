@@ -0,0 +1,275 @@
+// Package mongoaudit provides a MongoDB command monitor that logs
+// structured, redacted, size-capped command events via slog and records
+// their latency as a Prometheus histogram. It replaces ad hoc
+// log.Printf-based command monitors that risk leaking PII straight into
+// application logs.
+package mongoaudit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultMaxBytes is the default cap on a logged command payload's JSON
+// size, applied when Config.MaxBytes is unset.
+const DefaultMaxBytes = 4096
+
+// RequestIDFunc extracts a correlation ID from ctx for log correlation, e.g.
+// requestid.FromContext. A nil RequestIDFunc omits the field.
+type RequestIDFunc func(ctx context.Context) string
+
+// RedactionPolicy decides which command fields are hidden from logs. Keys
+// are matched case-insensitively at any depth of the command document.
+type RedactionPolicy struct {
+	// HashKeys are replaced by a short hash of their value, so repeated
+	// values stay recognizable without exposing the underlying data.
+	HashKeys []string
+	// DropKeys are removed entirely.
+	DropKeys []string
+}
+
+// DefaultRedactionPolicy hashes common free-text/PII fields and drops the
+// most sensitive ones outright. Callers with their own schema should build
+// a RedactionPolicy that matches it instead.
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{
+		HashKeys: []string{"description", "email"},
+		DropKeys: []string{"ssn", "password", "token"},
+	}
+}
+
+// Config configures a Monitor.
+type Config struct {
+	// Logger receives the structured command logs. Defaults to slog.Default().
+	Logger *slog.Logger
+	// Redact is applied to every logged command payload. Defaults to
+	// DefaultRedactionPolicy().
+	Redact RedactionPolicy
+	// MaxBytes caps the JSON size of a logged command payload. Defaults to
+	// DefaultMaxBytes.
+	MaxBytes int
+	// RequestID extracts a correlation ID to attach to every log line.
+	// Optional.
+	RequestID RequestIDFunc
+	// Registerer registers the command-latency histogram. Defaults to
+	// prometheus.DefaultRegisterer.
+	Registerer prometheus.Registerer
+}
+
+// Monitor builds an event.CommandMonitor that logs redacted, size-capped
+// command events and records their latency.
+type Monitor struct {
+	cfg     Config
+	latency *prometheus.HistogramVec
+}
+
+// NewMonitor creates a Monitor from cfg, applying defaults for any unset field.
+func NewMonitor(cfg Config) *Monitor {
+	if cfg.Logger == nil {
+		cfg.Logger = slog.Default()
+	}
+	if len(cfg.Redact.HashKeys) == 0 && len(cfg.Redact.DropKeys) == 0 {
+		cfg.Redact = DefaultRedactionPolicy()
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultMaxBytes
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "mongo_command_duration_seconds",
+		Help: "Duration of MongoDB commands, labeled by command name and success.",
+	}, []string{"command", "success"})
+
+	if err := cfg.Registerer.Register(latency); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			latency = are.ExistingCollector.(*prometheus.HistogramVec)
+		} else {
+			cfg.Logger.Warn("mongoaudit: failed to register latency histogram", "error", err)
+		}
+	}
+
+	return &Monitor{cfg: cfg, latency: latency}
+}
+
+// CommandMonitor returns the event.CommandMonitor to pass to
+// options.Client().SetMonitor.
+func (m *Monitor) CommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *Monitor) started(ctx context.Context, evt *event.CommandStartedEvent) {
+	var doc bson.M
+	if err := bson.Unmarshal(evt.Command, &doc); err != nil {
+		m.cfg.Logger.Error("mongo_command_decode_failed", "command", evt.CommandName, "error", err)
+		return
+	}
+
+	payload := m.relevantParts(evt.CommandName, doc)
+
+	m.cfg.Logger.Info("mongo_command_started",
+		"command", evt.CommandName,
+		"database", evt.DatabaseName,
+		"request_id", m.requestID(ctx),
+		"payload", m.redactAndTruncate(payload),
+	)
+}
+
+func (m *Monitor) succeeded(ctx context.Context, evt *event.CommandSucceededEvent) {
+	m.latency.WithLabelValues(evt.CommandName, "true").Observe(evt.Duration.Seconds())
+
+	m.cfg.Logger.Info("mongo_command_succeeded",
+		"command", evt.CommandName,
+		"database", evt.DatabaseName,
+		"request_id", m.requestID(ctx),
+		"duration_ms", evt.Duration.Milliseconds(),
+	)
+}
+
+func (m *Monitor) failed(ctx context.Context, evt *event.CommandFailedEvent) {
+	m.latency.WithLabelValues(evt.CommandName, "false").Observe(evt.Duration.Seconds())
+
+	m.cfg.Logger.Error("mongo_command_failed",
+		"command", evt.CommandName,
+		"database", evt.DatabaseName,
+		"request_id", m.requestID(ctx),
+		"duration_ms", evt.Duration.Milliseconds(),
+		"error", evt.Failure,
+	)
+}
+
+func (m *Monitor) requestID(ctx context.Context) string {
+	if m.cfg.RequestID == nil {
+		return ""
+	}
+	return m.cfg.RequestID(ctx)
+}
+
+// relevantParts extracts the parts of a command document worth logging,
+// skipping session/cluster metadata that's noise for every command.
+func (m *Monitor) relevantParts(commandName string, doc bson.M) bson.M {
+	parts := bson.M{}
+
+	switch commandName {
+	case "find":
+		copyKeys(doc, parts, "filter", "sort", "limit", "skip")
+	case "insert":
+		copyKeys(doc, parts, "documents")
+	case "update":
+		copyKeys(doc, parts, "filter", "updates")
+	case "delete":
+		copyKeys(doc, parts, "deletes")
+	case "aggregate":
+		copyKeys(doc, parts, "pipeline")
+	case "count", "countDocuments":
+		copyKeys(doc, parts, "query")
+	default:
+		for k, v := range doc {
+			if k != "$clusterTime" && k != "$db" && k != "lsid" && k != "$readPreference" {
+				parts[k] = v
+			}
+		}
+	}
+
+	return parts
+}
+
+func copyKeys(src, dst bson.M, keys ...string) {
+	for _, k := range keys {
+		if v, ok := src[k]; ok {
+			dst[k] = v
+		}
+	}
+}
+
+// redactAndTruncate applies m.cfg.Redact to doc and renders it as a
+// size-capped JSON string suitable for a single log field.
+func (m *Monitor) redactAndTruncate(doc bson.M) string {
+	redacted := redact(doc, m.cfg.Redact)
+
+	data, err := json.Marshal(redacted)
+	if err != nil {
+		return fmt.Sprintf("%v", redacted)
+	}
+	if len(data) <= m.cfg.MaxBytes {
+		return string(data)
+	}
+	return string(data[:m.cfg.MaxBytes]) + "...(truncated)"
+}
+
+func redact(doc bson.M, policy RedactionPolicy) bson.M {
+	out := make(bson.M, len(doc))
+	for k, v := range doc {
+		switch {
+		case hasKeyFold(policy.DropKeys, k):
+			continue
+		case hasKeyFold(policy.HashKeys, k):
+			out[k] = hashValue(v)
+		default:
+			out[k] = redactValue(v, policy)
+		}
+	}
+	return out
+}
+
+func redactValue(v interface{}, policy RedactionPolicy) interface{} {
+	switch val := v.(type) {
+	case bson.M:
+		return redact(val, policy)
+	case bson.D:
+		m := make(bson.M, len(val))
+		for _, e := range val {
+			m[e.Key] = e.Value
+		}
+		return redact(m, policy)
+	case primitive.A:
+		arr := make([]interface{}, len(val))
+		for i, item := range val {
+			arr[i] = redactValue(item, policy)
+		}
+		return arr
+	case []interface{}:
+		arr := make([]interface{}, len(val))
+		for i, item := range val {
+			arr[i] = redactValue(item, policy)
+		}
+		return arr
+	default:
+		return v
+	}
+}
+
+func hasKeyFold(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%v", v))
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:8])
+}
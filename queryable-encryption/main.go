@@ -8,6 +8,8 @@ import (
 	"os"
 	"time"
 
+	"queryable-encryption/internal/mongoaudit"
+
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -148,9 +150,14 @@ func main() {
 		"/Users/samuelmolling/Documents/github/mongodb-lab/queryable-encryption/mongo_crypt_shared_v1-macos-arm64-enterprise-8.0.3/lib/mongo_crypt_v1.dylib",
 	)
 
+	// Structured, redacting command monitor: logs every command via slog
+	// (PII fields hashed/dropped per mongoaudit.DefaultRedactionPolicy) and
+	// records command latency as a Prometheus histogram.
+	cmdMonitor := mongoaudit.NewMonitor(mongoaudit.Config{}).CommandMonitor()
+
 	encryptedClient, err := mongo.Connect(
 		context.TODO(),
-		options.Client().ApplyURI(uri).SetAutoEncryptionOptions(autoEncryptionOptions),
+		options.Client().ApplyURI(uri).SetAutoEncryptionOptions(autoEncryptionOptions).SetMonitor(cmdMonitor),
 	)
 	if err != nil {
 		log.Fatalf("Unable to connect to MongoDB: %v", err)
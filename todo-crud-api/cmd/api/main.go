@@ -4,19 +4,23 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
 	"time"
 
+	"todo-list-golang/internal/audit"
+	"todo-list-golang/internal/changestream"
 	"todo-list-golang/internal/config"
 	"todo-list-golang/internal/handler"
 	"todo-list-golang/internal/middleware"
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/mongoaudit"
 	"todo-list-golang/internal/repository"
+	"todo-list-golang/internal/requestid"
+	"todo-list-golang/internal/scheduler"
 	"todo-list-golang/internal/service"
+	"todo-list-golang/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -28,6 +32,19 @@ func main() {
 	// Configure Gin mode
 	gin.SetMode(cfg.Server.GinMode)
 
+	// Initialize tracing; a no-op provider until OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.OTel)
+	if err != nil {
+		log.Fatalf("Error initializing tracing: %v", err)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Printf("Error shutting down tracing: %v", err)
+		}
+	}()
+
 	// Connect to MongoDB
 	mongoClient, err := connectMongoDB(cfg)
 	if err != nil {
@@ -49,18 +66,85 @@ func main() {
 	}
 	cancel() // Cancel context after index operations are done
 
-	// Initialize repository, service, and handler
-	taskRepo := repository.NewTaskRepository(db, cfg.MongoDB.Collection)
-	taskService := service.NewTaskService(taskRepo)
-	taskHandler := handler.NewTaskHandler(taskService)
+	indexHandler := handler.NewIndexHandler(indexManager)
+
+	// Initialize the job subsystem and worker pool before the task service so
+	// task mutations can enqueue audit jobs
+	jobRepo := repository.NewJobRepository(db, cfg.Jobs.Collection)
+	jobService := service.NewJobService(jobRepo)
+	jobHandler := handler.NewJobHandler(jobService)
+
+	workerPool := service.NewWorkerPool(jobService, cfg.Jobs.WorkerPoolSize)
+	workerPool.RegisterHandler(models.JobKindTaskAudit, service.TaskAuditJobHandler())
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go workerPool.Start(workerCtx)
+
+	// Initialize the scheduler, which leases and executes cron-scheduled
+	// tasks straight off the tasks collection. This is a separate subsystem
+	// from the job worker pool above: that one drains one-off enqueued
+	// Jobs, this one re-runs Tasks on their own Schedule.
+	jobHistoryRepo := repository.NewJobHistoryRepository(db, cfg.Scheduler.HistoryCollection)
+	taskHistoryHandler := handler.NewTaskHistoryHandler(jobHistoryRepo)
+
+	schedWorker := scheduler.NewWorker(collection, jobHistoryRepo, time.Duration(cfg.Scheduler.PollIntervalSeconds)*time.Second)
+	schedWorker.RegisterHandler(models.JobKindReminder, scheduler.LogOnlyHandler())
+	schedWorker.RegisterHandler(models.JobKindRecurringCheck, scheduler.LogOnlyHandler())
+
+	schedCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	go schedWorker.Run(schedCtx)
+
+	// Initialize the schedule subsystem: unlike schedWorker above (which
+	// re-runs one existing Task via its own Schedule field), a Schedule
+	// creates a brand new Task from a template every cron occurrence.
+	scheduleRepo := repository.NewScheduleRepository(db, cfg.Scheduler.SchedulesCollection)
+	scheduleService := service.NewScheduleService(scheduleRepo, jobHistoryRepo)
+	scheduleHandler := handler.NewScheduleHandler(scheduleService)
+
+	scheduleWorker := scheduler.NewScheduleWorker(scheduleRepo, collection, jobHistoryRepo, time.Duration(cfg.Scheduler.PollIntervalSeconds)*time.Second)
+	go scheduleWorker.Run(schedCtx)
+
+	// Initialize the advisory locker used to serialize toggle operations
+	// across replicas, then the repository, service, and handler
+	locker := repository.NewLocker(db.Collection("locks"))
+	lockCtx, lockCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if err := locker.EnsureIndexes(lockCtx); err != nil {
+		log.Printf("Warning: Error ensuring lock indexes: %v", err)
+	}
+	lockCancel()
+
+	// Initialize the audit log, used to record who changed what on every
+	// task mutation and served back via the admin-only /audit endpoint
+	auditor := audit.NewMongoAuditor(db.Collection("audit_log"))
+	auditHandler := handler.NewAuditHandler(auditor)
+
+	taskRepo, err := repository.NewTaskRepositoryFromConfig(cfg, db)
+	if err != nil {
+		log.Fatalf("Error initializing task repository: %v", err)
+	}
+	taskService := service.NewTaskService(taskRepo,
+		service.WithJobService(jobService),
+		service.WithLocker(locker),
+		service.WithAuditor(auditor),
+	)
+	taskHandler := handler.NewTaskHandler(taskService, handler.WithMaxBatchSize(cfg.Bulk.MaxBatchSize))
+
+	// The task stream shares one change-stream cursor per distinct filter
+	// across every connected SSE client, so it's closed explicitly on
+	// shutdown rather than tied to a single request's context.
+	taskStream := changestream.NewTaskStream(collection)
+	defer taskStream.Close()
+	taskStreamHandler := handler.NewTaskStreamHandler(taskStream)
 
 	// Configure the router
-	router := setupRouter(cfg, taskHandler)
+	router := setupRouter(cfg, taskHandler, jobHandler, auditHandler, taskHistoryHandler, taskStreamHandler, indexHandler, scheduleHandler)
 
 	// Start the server
 	log.Printf("Server starting on port %s...", cfg.Server.Port)
 	if err := router.Run(":" + cfg.Server.Port); err != nil {
-		disconnectMongoDB(mongoClient) // Ensure cleanup before exit
+		disconnectMongoDB(mongoClient)               // Ensure cleanup before exit
 		log.Fatalf("Error starting server: %v", err) //nolint:gocritic // exitAfterDefer is acceptable here as we need to exit on critical failure
 	}
 }
@@ -70,91 +154,12 @@ func connectMongoDB(cfg *config.Config) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.MongoDB.Timeout)*time.Second)
 	defer cancel()
 
-	// Create a command monitor to log all MongoDB queries
-	cmdMonitor := &event.CommandMonitor{
-		Started: func(_ context.Context, evt *event.CommandStartedEvent) {
-			// Decode BSON command to a map
-			var commandDoc bson.M
-			if err := bson.Unmarshal(evt.Command, &commandDoc); err != nil {
-				log.Printf("[MongoDB Query] Command: %s | Error decoding: %v", evt.CommandName, err)
-				return
-			}
-
-			// Extract only the relevant query parts
-			relevantParts := bson.M{}
-
-			switch evt.CommandName {
-			case "find":
-				if filter, ok := commandDoc["filter"]; ok {
-					relevantParts["filter"] = filter
-				}
-				if sort, ok := commandDoc["sort"]; ok {
-					relevantParts["sort"] = sort
-				}
-				if limit, ok := commandDoc["limit"]; ok {
-					relevantParts["limit"] = limit
-				}
-				if skip, ok := commandDoc["skip"]; ok {
-					relevantParts["skip"] = skip
-				}
-
-			case "insert":
-				if documents, ok := commandDoc["documents"]; ok {
-					relevantParts["documents"] = documents
-				}
-
-			case "update":
-				if filter, ok := commandDoc["filter"]; ok {
-					relevantParts["filter"] = filter
-				}
-				if updates, ok := commandDoc["updates"]; ok {
-					relevantParts["updates"] = updates
-				}
-
-			case "delete":
-				if deletes, ok := commandDoc["deletes"]; ok {
-					relevantParts["deletes"] = deletes
-				}
-
-			case "aggregate":
-				if pipeline, ok := commandDoc["pipeline"]; ok {
-					relevantParts["pipeline"] = pipeline
-				}
-
-			case "count", "countDocuments":
-				if query, ok := commandDoc["query"]; ok {
-					relevantParts["query"] = query
-				}
-
-			default:
-				// For other commands, show the full command minus metadata
-				for k, v := range commandDoc {
-					if k != "$clusterTime" && k != "$db" && k != "lsid" && k != "$readPreference" {
-						relevantParts[k] = v
-					}
-				}
-			}
-
-			// Convert to pretty JSON
-			if len(relevantParts) > 0 {
-				queryJSON, err := json.MarshalIndent(relevantParts, "", "  ")
-				if err != nil {
-					log.Printf("[MongoDB Query] %s | %v", evt.CommandName, relevantParts)
-				} else {
-					log.Printf("[MongoDB Query] %s\n%s", evt.CommandName, string(queryJSON))
-				}
-			} else {
-				log.Printf("[MongoDB Query] %s (no filter/query)", evt.CommandName)
-			}
-		},
-		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
-			log.Printf("[MongoDB Query] %s | Duration: %v | ✓ SUCCESS\n", evt.CommandName, evt.Duration)
-		},
-		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
-			log.Printf("[MongoDB Query] %s | Duration: %v | ✗ FAILED | Error: %v\n",
-				evt.CommandName, evt.Duration, evt.Failure)
-		},
-	}
+	// Structured, redacting command monitor: logs every command via slog
+	// (PII fields hashed/dropped per mongoaudit.DefaultRedactionPolicy) and
+	// records command latency as a Prometheus histogram.
+	cmdMonitor := mongoaudit.NewMonitor(mongoaudit.Config{
+		RequestID: requestid.FromContext,
+	}).CommandMonitor()
 
 	// Client options with command monitor
 	clientOptions := options.Client().
@@ -189,13 +194,23 @@ func disconnectMongoDB(client *mongo.Client) {
 }
 
 // setupRouter configures routes and middlewares
-func setupRouter(cfg *config.Config, taskHandler *handler.TaskHandler) *gin.Engine {
+func setupRouter(cfg *config.Config, taskHandler *handler.TaskHandler, jobHandler *handler.JobHandler, auditHandler *handler.AuditHandler, taskHistoryHandler *handler.TaskHistoryHandler, taskStreamHandler *handler.TaskStreamHandler, indexHandler *handler.IndexHandler, scheduleHandler *handler.ScheduleHandler) *gin.Engine {
 	router := gin.New()
 
 	// Global middlewares
-	router.Use(gin.Recovery())                                     // Recovery middleware
-	router.Use(middleware.Logger())                                // Custom logger
-	router.Use(middleware.CORSMiddleware(cfg.CORS.AllowedOrigins)) // CORS
+	router.Use(gin.Recovery())                                       // Recovery middleware
+	router.Use(middleware.RequestID())                               // Correlation ID + tracing span
+	router.Use(middleware.Logger())                                  // Structured request logging
+	router.Use(middleware.CORSMiddleware(buildCORSConfig(cfg.CORS))) // CORS
+	router.Use(middleware.AuthContext())                             // Stashes doer/trace IDs for audit attribution
+	router.Use(middleware.DeadlineMiddleware(                        // Per-request context deadline
+		time.Duration(cfg.Deadline.DefaultTimeoutSeconds)*time.Second,
+		map[string]time.Duration{
+			"/api/v1/tasks/stream": time.Duration(cfg.Deadline.StreamTimeoutSeconds) * time.Second,
+			"/api/v1/jobs/stream":  time.Duration(cfg.Deadline.StreamTimeoutSeconds) * time.Second,
+		},
+		time.Duration(cfg.Deadline.MaxTimeoutSeconds)*time.Second,
+	))
 
 	// Serve static files (frontend)
 	router.Static("/static", "./web/static")
@@ -210,10 +225,48 @@ func setupRouter(cfg *config.Config, taskHandler *handler.TaskHandler) *gin.Engi
 			tasks.POST("", taskHandler.CreateTask)                       // POST /api/v1/tasks
 			tasks.GET("", taskHandler.GetAllTasks)                       // GET /api/v1/tasks
 			tasks.GET("/stats", taskHandler.GetTaskStats)                // GET /api/v1/tasks/stats
+			tasks.GET("/stream", taskStreamHandler.StreamTaskEvents)     // GET /api/v1/tasks/stream (SSE)
+			tasks.POST("/batch", taskHandler.BulkCreateTasks)            // POST /api/v1/tasks/batch
+			tasks.PATCH("/batch/toggle", taskHandler.BulkToggleTasks)    // PATCH /api/v1/tasks/batch/toggle
+			tasks.DELETE("/batch", taskHandler.BulkDeleteTasks)          // DELETE /api/v1/tasks/batch
 			tasks.GET("/:id", taskHandler.GetTaskByID)                   // GET /api/v1/tasks/:id
 			tasks.PUT("/:id", taskHandler.UpdateTask)                    // PUT /api/v1/tasks/:id
 			tasks.PATCH("/:id/toggle", taskHandler.ToggleTaskCompletion) // PATCH /api/v1/tasks/:id/toggle
+			tasks.PATCH("/:id/pause", taskHandler.PauseTask)             // PATCH /api/v1/tasks/:id/pause
+			tasks.PATCH("/:id/resume", taskHandler.ResumeTask)           // PATCH /api/v1/tasks/:id/resume
 			tasks.DELETE("/:id", taskHandler.DeleteTask)                 // DELETE /api/v1/tasks/:id
+			tasks.GET("/:id/history", taskHistoryHandler.GetTaskHistory) // GET /api/v1/tasks/:id/history
+		}
+
+		// Job routes
+		jobs := api.Group("/jobs")
+		{
+			jobs.POST("", jobHandler.EnqueueJob)            // POST /api/v1/jobs
+			jobs.GET("/stream", jobHandler.StreamJobEvents) // GET /api/v1/jobs/stream (SSE)
+		}
+
+		// Schedule routes: recurring task creation, distinct from a Task's
+		// own Schedule field (whose runs are listed at /tasks/:id/history)
+		schedules := api.Group("/schedules")
+		{
+			schedules.POST("", scheduleHandler.CreateSchedule)                      // POST /api/v1/schedules
+			schedules.GET("/:id/executions", scheduleHandler.GetScheduleExecutions) // GET /api/v1/schedules/:id/executions
+		}
+
+		// Audit routes (admin-only)
+		api.GET("/audit", middleware.RequireAdmin(), auditHandler.GetAuditLog) // GET /api/v1/audit
+
+		// Index admin routes, gated on a shared-secret token rather than the
+		// X-Admin stand-in header the audit log uses
+		admin := api.Group("/admin", middleware.RequireAdminToken(cfg.Admin.Token))
+		{
+			indexes := admin.Group("/indexes")
+			{
+				indexes.GET("", indexHandler.ListIndexes)             // GET /api/v1/admin/indexes
+				indexes.GET("/diff", indexHandler.DiffIndexes)        // GET /api/v1/admin/indexes/diff
+				indexes.POST("/rebuild", indexHandler.RebuildIndexes) // POST /api/v1/admin/indexes/rebuild
+				indexes.GET("/stats", indexHandler.GetIndexStats)     // GET /api/v1/admin/indexes/stats
+			}
 		}
 	}
 
@@ -227,3 +280,34 @@ func setupRouter(cfg *config.Config, taskHandler *handler.TaskHandler) *gin.Engi
 
 	return router
 }
+
+// buildCORSConfig translates config.CORSConfig's env-loadable settings into
+// a middleware.CORSConfig. A literal "*" in AllowedOrigins is treated as
+// middleware.WithAllowAll() rather than an exact-match origin string,
+// matching CORS_ALLOWED_ORIGINS' pre-existing default and meaning.
+func buildCORSConfig(cfg config.CORSConfig) *middleware.CORSConfig {
+	var opts []middleware.CORSConfigOption
+
+	var origins []string
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			opts = append(opts, middleware.WithAllowAll())
+			continue
+		}
+		origins = append(origins, origin)
+	}
+	if len(origins) > 0 {
+		opts = append(opts, middleware.WithOrigins(origins...))
+	}
+	if len(cfg.AllowedWildcards) > 0 {
+		opts = append(opts, middleware.WithWildcards(cfg.AllowedWildcards...))
+	}
+	if cfg.AllowCredentials {
+		opts = append(opts, middleware.WithCredentials())
+	}
+	if cfg.ExposedHeaders != "" {
+		opts = append(opts, middleware.WithExposedHeaders(cfg.ExposedHeaders))
+	}
+
+	return middleware.NewCORSConfig(opts...)
+}
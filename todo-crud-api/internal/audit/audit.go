@@ -0,0 +1,149 @@
+// Package audit records who changed what, following the shape of Forgejo's
+// admin.Task: every mutation is written to an audit_log collection with the
+// acting principal, the resource touched, and a before/after diff.
+package audit
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Entry is a single audit record.
+type Entry struct {
+	Action     string    `json:"action" bson:"action"`
+	Resource   string    `json:"resource" bson:"resource"`
+	ResourceID string    `json:"resourceId" bson:"resource_id"`
+	DoerID     string    `json:"doerId" bson:"doer_id"`
+	Before     bson.M    `json:"before,omitempty" bson:"before,omitempty"`
+	After      bson.M    `json:"after,omitempty" bson:"after,omitempty"`
+	At         time.Time `json:"at" bson:"at"`
+	RequestID  string    `json:"requestId,omitempty" bson:"request_id,omitempty"`
+}
+
+// Auditor records audit entries for resource mutations. Services depend on
+// this interface rather than *MongoAuditor so they stay easy to unit test.
+type Auditor interface {
+	Record(ctx context.Context, entry Entry) error
+}
+
+// ListFilter narrows a List query to a doer, a resource type, and/or a time
+// window. Zero values are treated as "no filter" for that field.
+type ListFilter struct {
+	DoerID   string
+	Resource string
+	From     time.Time
+	To       time.Time
+}
+
+// MongoAuditor is an Auditor backed by a Mongo collection. It also exposes
+// List, used by the admin audit-log endpoint.
+type MongoAuditor struct {
+	collection *mongo.Collection
+}
+
+// NewMongoAuditor creates a MongoAuditor writing to the given collection.
+func NewMongoAuditor(collection *mongo.Collection) *MongoAuditor {
+	return &MongoAuditor{collection: collection}
+}
+
+// Record inserts entry into the audit log, stamping At if it wasn't already set.
+func (a *MongoAuditor) Record(ctx context.Context, entry Entry) error {
+	if entry.At.IsZero() {
+		entry.At = time.Now()
+	}
+	_, err := a.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// List returns audit entries matching filter, most recent first.
+func (a *MongoAuditor) List(ctx context.Context, filter ListFilter) ([]Entry, error) {
+	bsonFilter := bson.M{}
+	if filter.DoerID != "" {
+		bsonFilter["doer_id"] = filter.DoerID
+	}
+	if filter.Resource != "" {
+		bsonFilter["resource"] = filter.Resource
+	}
+	if !filter.From.IsZero() || !filter.To.IsZero() {
+		window := bson.M{}
+		if !filter.From.IsZero() {
+			window["$gte"] = filter.From
+		}
+		if !filter.To.IsZero() {
+			window["$lte"] = filter.To
+		}
+		bsonFilter["at"] = window
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: -1}})
+	cursor, err := a.collection.Find(ctx, bsonFilter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}()
+
+	var entries []Entry
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []Entry{}
+	}
+	return entries, nil
+}
+
+// Diff converts before/after into BSON maps and keeps only the fields that
+// differ, so a wide document's audit entry doesn't repeat its unchanged
+// fields on every mutation. A nil before or after is treated as empty.
+func Diff(before, after any) (bson.M, bson.M, error) {
+	beforeM, err := toBSONMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterM, err := toBSONMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changedBefore, changedAfter := bson.M{}, bson.M{}
+	for key, afterVal := range afterM {
+		beforeVal, existed := beforeM[key]
+		if !existed || !reflect.DeepEqual(beforeVal, afterVal) {
+			changedAfter[key] = afterVal
+			if existed {
+				changedBefore[key] = beforeVal
+			}
+		}
+	}
+	for key, beforeVal := range beforeM {
+		if _, stillThere := afterM[key]; !stillThere {
+			changedBefore[key] = beforeVal
+		}
+	}
+	return changedBefore, changedAfter, nil
+}
+
+func toBSONMap(v any) (bson.M, error) {
+	if v == nil {
+		return bson.M{}, nil
+	}
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m bson.M
+	if err := bson.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
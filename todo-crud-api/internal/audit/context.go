@@ -0,0 +1,35 @@
+package audit
+
+import "context"
+
+type contextKey string
+
+const (
+	doerIDKey  contextKey = "audit.doerID"
+	traceIDKey contextKey = "audit.traceID"
+)
+
+// WithDoerID returns a copy of ctx carrying the acting principal's ID.
+func WithDoerID(ctx context.Context, doerID string) context.Context {
+	return context.WithValue(ctx, doerIDKey, doerID)
+}
+
+// DoerID returns the acting principal's ID stashed by middleware.AuthContext,
+// or "" if none was set.
+func DoerID(ctx context.Context) string {
+	id, _ := ctx.Value(doerIDKey).(string)
+	return id
+}
+
+// WithTraceID returns a copy of ctx carrying a trace ID correlating a
+// request's audit entries.
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey, traceID)
+}
+
+// TraceID returns the trace ID stashed by middleware.AuthContext, or "" if
+// none was set.
+func TraceID(ctx context.Context) string {
+	id, _ := ctx.Value(traceIDKey).(string)
+	return id
+}
@@ -0,0 +1,250 @@
+// Package changestream fans out MongoDB change-stream events for the tasks
+// collection to Server-Sent Event subscribers, sharing one underlying
+// change stream per distinct filter instead of opening one per connection.
+package changestream
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CoalesceWindow is how long a filterStream batches consecutive events
+// before flushing them to subscribers, so a burst of writes (e.g. a bulk
+// import) reaches each subscriber as one batch rather than one channel send
+// per document. The SSE handler still writes one event per document.
+const CoalesceWindow = 100 * time.Millisecond
+
+// Event is a single change-stream event surfaced to subscribers. ID is the
+// change stream's resume token, base64-encoded so a reconnecting client can
+// echo it back via the Last-Event-ID header.
+type Event struct {
+	ID            string `json:"id"`
+	OperationType string `json:"operationType"`
+	DocumentKey   bson.M `json:"documentKey,omitempty"`
+	FullDocument  bson.M `json:"fullDocument,omitempty"`
+}
+
+// TaskStream manages one underlying MongoDB change stream per distinct
+// filter over the tasks collection, fanning each stream's events out to any
+// number of subscribers.
+type TaskStream struct {
+	collection *mongo.Collection
+
+	mu      sync.Mutex
+	streams map[string]*filterStream
+}
+
+// NewTaskStream creates a TaskStream over the tasks collection.
+func NewTaskStream(collection *mongo.Collection) *TaskStream {
+	return &TaskStream{
+		collection: collection,
+		streams:    make(map[string]*filterStream),
+	}
+}
+
+// Subscribe returns a channel of batched events matching match (a $match
+// stage applied to the change event, e.g. {"fullDocument.priority": "high"})
+// and an unsubscribe func. resumeAfter, if non-empty, is a resume token from
+// a previous Event.ID; it is only honored when it starts the underlying
+// stream for this filter, i.e. for the first subscriber, since every later
+// subscriber shares that same stream going forward.
+func (s *TaskStream) Subscribe(match bson.M, resumeAfter string) (<-chan []Event, func()) {
+	key := filterKey(match)
+
+	s.mu.Lock()
+	fs, ok := s.streams[key]
+	if !ok {
+		fs = newFilterStream(s.collection, match)
+		s.streams[key] = fs
+	}
+	s.mu.Unlock()
+
+	return fs.subscribe(resumeAfter)
+}
+
+// Close shuts down every running change stream. Call this on server
+// shutdown so the underlying cursors aren't left open against MongoDB.
+func (s *TaskStream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, fs := range s.streams {
+		fs.close()
+		delete(s.streams, key)
+	}
+}
+
+func filterKey(match bson.M) string {
+	data, _ := json.Marshal(match)
+	return string(data)
+}
+
+// filterStream is the single change stream backing every subscriber for one
+// filter, so N SSE connections on the same query share one cursor against
+// MongoDB instead of each opening their own.
+type filterStream struct {
+	collection *mongo.Collection
+	match      bson.M
+
+	mu          sync.Mutex
+	subscribers map[chan []Event]struct{}
+	cancel      context.CancelFunc
+}
+
+func newFilterStream(collection *mongo.Collection, match bson.M) *filterStream {
+	return &filterStream{
+		collection:  collection,
+		match:       match,
+		subscribers: make(map[chan []Event]struct{}),
+	}
+}
+
+func (fs *filterStream) subscribe(resumeAfter string) (<-chan []Event, func()) {
+	ch := make(chan []Event, 16)
+
+	fs.mu.Lock()
+	fs.subscribers[ch] = struct{}{}
+	if fs.cancel == nil {
+		runCtx, cancel := context.WithCancel(context.Background())
+		fs.cancel = cancel
+		go fs.run(runCtx, resumeAfter)
+	}
+	fs.mu.Unlock()
+
+	unsubscribe := func() {
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		if _, ok := fs.subscribers[ch]; ok {
+			delete(fs.subscribers, ch)
+			close(ch)
+		}
+		if len(fs.subscribers) == 0 && fs.cancel != nil {
+			fs.cancel()
+			fs.cancel = nil
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (fs *filterStream) close() {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if fs.cancel != nil {
+		fs.cancel()
+		fs.cancel = nil
+	}
+	for ch := range fs.subscribers {
+		delete(fs.subscribers, ch)
+		close(ch)
+	}
+}
+
+// run opens the change stream and coalesces its events into batches flushed
+// at most once per CoalesceWindow, until ctx is canceled or the stream errors.
+func (fs *filterStream) run(ctx context.Context, resumeAfter string) {
+	pipeline := mongo.Pipeline{}
+	if len(fs.match) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: fs.match}})
+	}
+
+	opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+	if resumeAfter != "" {
+		if token, err := decodeResumeToken(resumeAfter); err == nil {
+			opts.SetResumeAfter(token)
+		} else {
+			log.Printf("changestream: ignoring invalid resume token: %v", err)
+		}
+	}
+
+	cursor, err := fs.collection.Watch(ctx, pipeline, opts)
+	if err != nil {
+		log.Printf("changestream: Watch failed: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				continue
+			}
+
+			event := Event{ID: encodeResumeToken(cursor.ResumeToken())}
+			if op, ok := doc["operationType"].(string); ok {
+				event.OperationType = op
+			}
+			if dk, ok := doc["documentKey"].(bson.M); ok {
+				event.DocumentKey = dk
+			}
+			if fd, ok := doc["fullDocument"].(bson.M); ok {
+				event.FullDocument = fd
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var batch []Event
+	var flush <-chan time.Time
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				if err := cursor.Err(); err != nil {
+					log.Printf("changestream: cursor error: %v", err)
+				}
+				return
+			}
+			if len(batch) == 0 {
+				flush = time.After(CoalesceWindow)
+			}
+			batch = append(batch, event)
+		case <-flush:
+			fs.broadcast(batch)
+			batch = nil
+			flush = nil
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (fs *filterStream) broadcast(batch []Event) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	for ch := range fs.subscribers {
+		select {
+		case ch <- batch:
+		default:
+			// Slow subscriber: drop the batch rather than block the change stream.
+		}
+	}
+}
+
+func encodeResumeToken(token bson.Raw) string {
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodeResumeToken(s string) (bson.Raw, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resume token: %w", err)
+	}
+	return bson.Raw(data), nil
+}
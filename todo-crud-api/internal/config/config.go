@@ -12,9 +12,60 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server  ServerConfig
-	MongoDB MongoDBConfig
-	CORS    CORSConfig
+	Server    ServerConfig
+	MongoDB   MongoDBConfig
+	Storage   StorageConfig
+	CORS      CORSConfig
+	Jobs      JobsConfig
+	Scheduler SchedulerConfig
+	OTel      OTelConfig
+	Admin     AdminConfig
+	Deadline  DeadlineConfig
+	Bulk      BulkConfig
+}
+
+// AdminConfig holds settings for operator-only routes like
+// /api/v1/admin/indexes, which are gated on a shared-secret token instead of
+// the X-Admin stand-in header used by the audit endpoint.
+type AdminConfig struct {
+	Token string
+}
+
+// StorageConfig selects which TaskRepository backend is used. Backend picks
+// which of the MongoDB/Postgres settings is actually required; the memory
+// backend needs neither. The job, lock, and audit subsystems are unaffected
+// by this setting and still talk to MongoDB directly.
+type StorageConfig struct {
+	Backend  string // mongo (default), memory, or postgres
+	Postgres PostgresConfig
+}
+
+// PostgresConfig holds the connection string for the postgres TaskRepository backend.
+type PostgresConfig struct {
+	DSN string
+}
+
+// OTelConfig holds OpenTelemetry tracing configuration. Endpoint is left
+// empty by default, which keeps the tracer provider a no-op until an
+// exporter is configured.
+type OTelConfig struct {
+	ExporterOTLPEndpoint string
+	ServiceName          string
+}
+
+// JobsConfig holds configuration for the background job worker pool
+type JobsConfig struct {
+	WorkerPoolSize int
+	Collection     string
+}
+
+// SchedulerConfig holds configuration for the internal/scheduler Worker
+// that executes cron-scheduled tasks, and the ScheduleWorker that creates
+// new tasks from a Schedule's template.
+type SchedulerConfig struct {
+	PollIntervalSeconds int
+	HistoryCollection   string
+	SchedulesCollection string
 }
 
 // ServerConfig holds HTTP server configuration
@@ -31,9 +82,30 @@ type MongoDBConfig struct {
 	Timeout    int
 }
 
-// CORSConfig holds CORS configuration
+// CORSConfig holds CORS configuration. AllowedWildcards and AllowedOrigins
+// are passed to middleware.WithWildcards/middleware.WithOrigins; compiled
+// regex patterns and a custom match function aren't env-configurable and
+// are wired in code via middleware.WithPatterns/middleware.WithMatchFunc.
 type CORSConfig struct {
-	AllowedOrigins []string
+	AllowedOrigins   []string
+	AllowedWildcards []string
+	AllowCredentials bool
+	ExposedHeaders   string
+}
+
+// DeadlineConfig holds middleware.DeadlineMiddleware's settings.
+// StreamTimeoutSeconds overrides the default for the SSE routes
+// (/tasks/stream, /jobs/stream), which are meant to stay open far longer
+// than a normal request.
+type DeadlineConfig struct {
+	DefaultTimeoutSeconds int
+	MaxTimeoutSeconds     int
+	StreamTimeoutSeconds  int
+}
+
+// BulkConfig holds settings for the /tasks/batch* endpoints.
+type BulkConfig struct {
+	MaxBatchSize int
 }
 
 // Load loads configuration from .env file
@@ -54,14 +126,60 @@ func Load() *Config {
 			Collection: getEnv("MONGODB_COLLECTION", "tasks"),
 			Timeout:    getEnvAsInt("MONGODB_TIMEOUT", 10),
 		},
+		Storage: StorageConfig{
+			Backend: getEnv("STORAGE_BACKEND", "mongo"),
+			Postgres: PostgresConfig{
+				DSN: getEnv("POSTGRES_DSN", ""),
+			},
+		},
 		CORS: CORSConfig{
-			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
+			AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
+			AllowedWildcards: getEnvAsSlice("CORS_ALLOWED_WILDCARDS", []string{}),
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", false),
+			ExposedHeaders:   getEnv("CORS_EXPOSED_HEADERS", ""),
+		},
+		Jobs: JobsConfig{
+			WorkerPoolSize: getEnvAsInt("JOBS_WORKER_POOL_SIZE", 4),
+			Collection:     getEnv("JOBS_COLLECTION", "jobs"),
+		},
+		Scheduler: SchedulerConfig{
+			PollIntervalSeconds: getEnvAsInt("SCHEDULER_POLL_INTERVAL_SECONDS", 5),
+			HistoryCollection:   getEnv("SCHEDULER_HISTORY_COLLECTION", "job_history"),
+			SchedulesCollection: getEnv("SCHEDULER_SCHEDULES_COLLECTION", "schedules"),
+		},
+		OTel: OTelConfig{
+			ExporterOTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			ServiceName:          getEnv("OTEL_SERVICE_NAME", "todo-crud-api"),
+		},
+		Admin: AdminConfig{
+			Token: getEnv("ADMIN_TOKEN", ""),
+		},
+		Deadline: DeadlineConfig{
+			DefaultTimeoutSeconds: getEnvAsInt("REQUEST_DEADLINE_SECONDS", 10),
+			MaxTimeoutSeconds:     getEnvAsInt("REQUEST_DEADLINE_MAX_SECONDS", 60),
+			StreamTimeoutSeconds:  getEnvAsInt("REQUEST_DEADLINE_STREAM_SECONDS", 3600),
+		},
+		Bulk: BulkConfig{
+			MaxBatchSize: getEnvAsInt("BULK_MAX_BATCH_SIZE", 100),
 		},
 	}
 
-	// Validate required configuration
-	if config.MongoDB.URI == "" {
-		log.Fatal("MONGODB_URI is required. Please configure it in .env file")
+	// Validate backend-specific configuration. Note that the job, lock, and
+	// audit subsystems always talk to MongoDB regardless of Storage.Backend,
+	// so MONGODB_URI is still effectively required unless those are unused.
+	switch config.Storage.Backend {
+	case "", "mongo":
+		if config.MongoDB.URI == "" {
+			log.Fatal("MONGODB_URI is required when STORAGE_BACKEND is mongo. Please configure it in .env file")
+		}
+	case "postgres":
+		if config.Storage.Postgres.DSN == "" {
+			log.Fatal("POSTGRES_DSN is required when STORAGE_BACKEND is postgres. Please configure it in .env file")
+		}
+	case "memory":
+		// No backend-specific settings required.
+	default:
+		log.Fatalf("Unknown STORAGE_BACKEND %q: use mongo, memory, or postgres", config.Storage.Backend)
 	}
 
 	return config
@@ -92,6 +210,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsBool gets an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		log.Printf("Warning: Error converting %s to bool, using default value %t", key, defaultValue)
+		return defaultValue
+	}
+
+	return value
+}
+
 // getEnvAsSlice gets an environment variable as slice (comma separated)
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	valueStr := os.Getenv(key)
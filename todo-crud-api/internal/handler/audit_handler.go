@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"todo-list-golang/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditHandler serves the admin audit-log endpoint.
+type AuditHandler struct {
+	auditor *audit.MongoAuditor
+}
+
+// NewAuditHandler creates a new handler instance.
+func NewAuditHandler(auditor *audit.MongoAuditor) *AuditHandler {
+	return &AuditHandler{auditor: auditor}
+}
+
+// GetAuditLog godoc
+// @Summary List audit log entries
+// @Description Returns audit entries, optionally filtered by doer, resource, and time window
+// @Tags audit
+// @Produce json
+// @Param doer query string false "Filter by doer ID"
+// @Param resource query string false "Filter by resource type (e.g. task)"
+// @Param from query string false "Filter entries at or after this RFC3339 timestamp"
+// @Param to query string false "Filter entries at or before this RFC3339 timestamp"
+// @Success 200 {array} audit.Entry
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /audit [get]
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	filter := audit.ListFilter{
+		DoerID:   c.Query("doer"),
+		Resource: c.Query("resource"),
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := time.Parse(time.RFC3339, fromStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid 'from' timestamp: " + err.Error()})
+			return
+		}
+		filter.From = from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := time.Parse(time.RFC3339, toStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid 'to' timestamp: " + err.Error()})
+			return
+		}
+		filter.To = to
+	}
+
+	entries, err := h.auditor.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"todo-list-golang/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IndexHandler exposes the tasks collection's IndexManager over HTTP for
+// operators, so index definitions can change and take effect without a
+// redeploy.
+type IndexHandler struct {
+	indexManager *repository.IndexManager
+}
+
+// NewIndexHandler creates a new index handler instance.
+func NewIndexHandler(indexManager *repository.IndexManager) *IndexHandler {
+	return &IndexHandler{indexManager: indexManager}
+}
+
+// ListIndexes godoc
+// @Summary List indexes on the tasks collection
+// @Description Returns the raw index specs as reported by the server
+// @Tags admin
+// @Produce json
+// @Success 200 {array} object
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/indexes [get]
+func (h *IndexHandler) ListIndexes(c *gin.Context) {
+	indexes, err := h.indexManager.ListIndexes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error listing indexes"})
+		return
+	}
+	c.JSON(http.StatusOK, indexes)
+}
+
+// DiffIndexes godoc
+// @Summary Diff registered indexes against the live collection
+// @Description Reports index names missing from the collection and index names present but not registered
+// @Tags admin
+// @Produce json
+// @Success 200 {object} repository.IndexDiff
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/indexes/diff [get]
+func (h *IndexHandler) DiffIndexes(c *gin.Context) {
+	diff, err := h.indexManager.DiffIndexes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error diffing indexes"})
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// RebuildIndexes godoc
+// @Summary Rebuild all indexes on the tasks collection
+// @Description Drops every non-_id index and recreates the registered set, useful after changing an IndexSpec
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/indexes/rebuild [post]
+func (h *IndexHandler) RebuildIndexes(c *gin.Context) {
+	if err := h.indexManager.RebuildIndexes(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error rebuilding indexes"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "rebuilt"})
+}
+
+// GetIndexStats godoc
+// @Summary Index usage statistics
+// @Description Returns $indexStats for the tasks collection
+// @Tags admin
+// @Produce json
+// @Success 200 {array} object
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/indexes/stats [get]
+func (h *IndexHandler) GetIndexStats(c *gin.Context) {
+	stats, err := h.indexManager.GetIndexStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching index stats"})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"todo-list-golang/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// JobHandler exposes the job/execution subsystem over HTTP.
+type JobHandler struct {
+	service service.JobService
+}
+
+// NewJobHandler creates a new job handler instance.
+func NewJobHandler(service service.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// StreamJobEvents godoc
+// @Summary Stream job status changes
+// @Description Pushes job status changes as Server-Sent Events
+// @Tags jobs
+// @Produce text/event-stream
+// @Success 200
+// @Router /jobs/stream [get]
+func (h *JobHandler) StreamJobEvents(c *gin.Context) {
+	events, unsubscribe := h.service.Subscribe()
+	defer unsubscribe()
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	ctx := c.Request.Context()
+
+	c.Stream(func(w gin.ResponseWriter) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("job", event)
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
+
+// EnqueueJob godoc
+// @Summary Enqueue a job
+// @Description Enqueues a background job of the given kind for demonstration/testing
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Job
+// @Failure 400 {object} ErrorResponse
+// @Router /jobs [post]
+func (h *JobHandler) EnqueueJob(c *gin.Context) {
+	var req struct {
+		Kind    string         `json:"kind" binding:"required"`
+		Payload map[string]any `json:"payload"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid data: %v", err)})
+		return
+	}
+
+	job, err := h.service.EnqueueJob(c.Request.Context(), req.Kind, bson.M(req.Payload))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, job)
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler exposes recurring task-creation Schedules over HTTP.
+type ScheduleHandler struct {
+	service service.ScheduleService
+}
+
+// NewScheduleHandler creates a new schedule handler instance.
+func NewScheduleHandler(service service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: service}
+}
+
+// scheduleExecutionsResponse is the GET /schedules/{id}/executions body:
+// the raw JobHistory entries plus the aggregate counts clients would
+// otherwise have to derive themselves.
+type scheduleExecutionsResponse struct {
+	Executions []*models.JobHistory   `json:"executions"`
+	Counts     models.ExecutionCounts `json:"counts"`
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring task schedule
+// @Description Creates a Schedule that creates a new Task from a template every cron occurrence
+// @Tags schedules
+// @Accept json
+// @Produce json
+// @Success 201 {object} models.Schedule
+// @Failure 400 {object} ErrorResponse
+// @Router /schedules [post]
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	var req models.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("Invalid data: %v", err)})
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Request.Context(), &req)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// GetScheduleExecutions godoc
+// @Summary List a schedule's execution history
+// @Description Returns a Schedule's JobHistory entries, most recent first, with aggregate counts by status
+// @Tags schedules
+// @Produce json
+// @Param id path string true "Schedule ID"
+// @Success 200 {object} scheduleExecutionsResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /schedules/{id}/executions [get]
+func (h *ScheduleHandler) GetScheduleExecutions(c *gin.Context) {
+	id := c.Param("id")
+
+	executions, counts, err := h.service.GetExecutions(c.Request.Context(), id)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduleExecutionsResponse{Executions: executions, Counts: counts})
+}
@@ -2,25 +2,58 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"todo-list-golang/internal/middleware"
 	"todo-list-golang/internal/models"
 	"todo-list-golang/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
+// defaultPageSize and maxPageSize bound GetAllTasks' page_size query
+// parameter: unset defaults to defaultPageSize, and anything over
+// maxPageSize is rejected rather than silently clamped.
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// defaultMaxBatchSize bounds the number of items a /tasks/batch* request may
+// carry, used unless overridden by WithMaxBatchSize.
+const defaultMaxBatchSize = 100
+
 // TaskHandler manages HTTP requests related to tasks
 type TaskHandler struct {
-	service service.TaskService
+	service      service.TaskService
+	maxBatchSize int
+}
+
+// TaskHandlerOption configures optional settings on NewTaskHandler.
+type TaskHandlerOption func(*TaskHandler)
+
+// WithMaxBatchSize overrides the default max size for /tasks/batch* requests.
+func WithMaxBatchSize(max int) TaskHandlerOption {
+	return func(h *TaskHandler) {
+		h.maxBatchSize = max
+	}
 }
 
 // NewTaskHandler creates a new handler instance
-func NewTaskHandler(service service.TaskService) *TaskHandler {
-	return &TaskHandler{
-		service: service,
+func NewTaskHandler(service service.TaskService, opts ...TaskHandlerOption) *TaskHandler {
+	h := &TaskHandler{
+		service:      service,
+		maxBatchSize: defaultMaxBatchSize,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	return h
 }
 
 // ErrorResponse represents an error response
@@ -34,6 +67,55 @@ type SuccessResponse struct {
 	Data    any    `json:"data,omitempty"`
 }
 
+// statusForCode maps a service.Code to the HTTP status it should produce.
+func statusForCode(code service.Code) int {
+	switch code {
+	case service.ErrNotFound:
+		return http.StatusNotFound
+	case service.ErrConflict:
+		return http.StatusConflict
+	case service.ErrDependencyUnavailable:
+		return http.StatusServiceUnavailable
+	case service.ErrInvalidInput, service.ErrValidation:
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// writeServiceError maps err to an HTTP response. A *service.ServiceError is
+// mapped by its Code via statusForCode; anything else falls back to the
+// pre-existing ErrTaskNotFound string-compare so service methods that don't
+// yet return a ServiceError keep behaving the same.
+func writeServiceError(c *gin.Context, err error) {
+	var svcErr *service.ServiceError
+	if errors.As(err, &svcErr) {
+		c.JSON(statusForCode(svcErr.Code), ErrorResponse{Error: svcErr.Message})
+		return
+	}
+
+	if err.Error() == models.ErrTaskNotFound {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+}
+
+// parseIfMatch extracts a numeric version from an If-Match header value,
+// tolerating the quoted-string form ("3") most HTTP clients send. It
+// reports false if header is absent or not a valid version.
+func parseIfMatch(header string) (int, bool) {
+	trimmed := strings.Trim(header, `"`)
+	if trimmed == "" {
+		return 0, false
+	}
+	version, err := strconv.Atoi(trimmed)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
 // CreateTask godoc
 // @Summary Create a new task
 // @Description Creates a new task in the system
@@ -57,7 +139,7 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	// Call the service
 	task, err := h.service.CreateTask(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
@@ -66,12 +148,19 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 
 // GetAllTasks godoc
 // @Summary List all tasks
-// @Description Returns all tasks with optional filters
+// @Description Returns a page of tasks matching the given filters, most recent first unless sort says otherwise. The response carries X-Total-Count (the filter's total match count, ignoring paging) and an RFC 5988 Link header with rel="next"/"prev" URLs for the adjacent pages; rel="prev" is omitted on page 1, rel="next" once there's no further page.
 // @Tags tasks
 // @Produce json
 // @Param completed query bool false "Filter by completion status"
 // @Param priority query string false "Filter by priority (low, medium, high)"
+// @Param page query int false "Page number for the Link header's rel=prev/next URLs, default 1"
+// @Param page_size query int false "Max tasks to return, default 20, rejected above 100"
+// @Param sort query string false "Sort field, optionally prefixed with - for descending; one of createdAt, dueDate, priority (default -createdAt)"
+// @Param cursor query string false "Opaque cursor from a previous response's Link rel=next URL"
 // @Success 200 {array} models.Task
+// @Header 200 {string} X-Total-Count "Total tasks matching the filter, ignoring paging"
+// @Header 200 {string} Link "rel=\"next\"/\"prev\" page URLs, RFC 5988"
+// @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /tasks [get]
 func (h *TaskHandler) GetAllTasks(c *gin.Context) {
@@ -91,16 +180,87 @@ func (h *TaskHandler) GetAllTasks(c *gin.Context) {
 		filter.Priority = priority
 	}
 
+	pagination := &models.Pagination{Page: 1, PageSize: defaultPageSize}
+	if pageStr := c.Query("page"); pageStr != "" {
+		page, err := strconv.Atoi(pageStr)
+		if err != nil || page < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid page: must be a positive integer"})
+			return
+		}
+		pagination.Page = page
+	}
+
+	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
+		pageSize, err := strconv.Atoi(pageSizeStr)
+		if err != nil || pageSize < 1 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid page_size: must be a positive integer"})
+			return
+		}
+		if pageSize > maxPageSize {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("page_size exceeds max of %d", maxPageSize)})
+			return
+		}
+		pagination.PageSize = pageSize
+	}
+
+	pagination.Sort = c.Query("sort")
+	if _, _, ok := models.ParseSort(pagination.Sort); !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid sort field %q", pagination.Sort)})
+		return
+	}
+	pagination.Cursor = c.Query("cursor")
+
 	// Call the service
-	tasks, err := h.service.GetAllTasks(c.Request.Context(), filter)
+	tasks, out, err := h.service.GetAllTasks(c.Request.Context(), filter, pagination)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
+	c.Header("X-Total-Count", strconv.FormatInt(out.TotalCount, 10))
+	if link := buildLinkHeader(c, out); link != "" {
+		c.Header("Link", link)
+	}
 	c.JSON(http.StatusOK, tasks)
 }
 
+// buildLinkHeader returns the RFC 5988 Link header value advertising the
+// adjacent pages for c's request: rel="next" if out.NextCursor says there
+// is one, rel="prev" unless out is already on page 1. Prev re-requests its
+// page by number rather than a cursor, since FindAll only hands back a
+// cursor for the page after the one it returned.
+func buildLinkHeader(c *gin.Context, out *models.Pagination) string {
+	var links []string
+	if out.NextCursor != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(c, out.Page+1, out.PageSize, out.Sort, out.NextCursor)))
+	}
+	if out.Page > 1 {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(c, out.Page-1, out.PageSize, out.Sort, "")))
+	}
+	return strings.Join(links, ", ")
+}
+
+// pageURL rebuilds c's request URL with its page/page_size/sort/cursor
+// query parameters replaced by the given values, for buildLinkHeader.
+func pageURL(c *gin.Context, page, pageSize int, sort, cursor string) string {
+	u := *c.Request.URL
+	q := u.Query()
+	q.Set("page", strconv.Itoa(page))
+	q.Set("page_size", strconv.Itoa(pageSize))
+	if sort != "" {
+		q.Set("sort", sort)
+	} else {
+		q.Del("sort")
+	}
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	} else {
+		q.Del("cursor")
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 // GetTaskByID godoc
 // @Summary Get a task by ID
 // @Description Returns the details of a specific task
@@ -116,14 +276,11 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 
 	task, err := h.service.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == models.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
+	c.Header("ETag", task.ETag())
 	c.JSON(http.StatusOK, task)
 }
 
@@ -135,9 +292,11 @@ func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Task ID"
 // @Param task body models.UpdateTaskRequest true "Update data"
+// @Param If-Match header string false "Expected task version, as returned in a prior ETag"
 // @Success 200 {object} models.Task
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
 // @Router /tasks/{id} [put]
 func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	id := c.Param("id")
@@ -148,16 +307,19 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
+	if req.ExpectedVersion == nil {
+		if version, ok := parseIfMatch(c.GetHeader("If-Match")); ok {
+			req.ExpectedVersion = &version
+		}
+	}
+
 	task, err := h.service.UpdateTask(c.Request.Context(), id, &req)
 	if err != nil {
-		if err.Error() == models.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
+	c.Header("ETag", task.ETag())
 	c.JSON(http.StatusOK, task)
 }
 
@@ -176,11 +338,57 @@ func (h *TaskHandler) ToggleTaskCompletion(c *gin.Context) {
 
 	task, err := h.service.ToggleTaskCompletion(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == models.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// PauseTask godoc
+// @Summary Pause a task
+// @Description Transitions an active task to paused
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/pause [patch]
+func (h *TaskHandler) PauseTask(c *gin.Context) {
+	id := c.Param("id")
+
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	_ = c.ShouldBindJSON(&req) // reason is optional
+
+	task, err := h.service.PauseTask(c.Request.Context(), id, req.Reason)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// ResumeTask godoc
+// @Summary Resume a task
+// @Description Transitions a paused task back to active
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /tasks/{id}/resume [patch]
+func (h *TaskHandler) ResumeTask(c *gin.Context) {
+	id := c.Param("id")
+
+	task, err := h.service.ResumeTask(c.Request.Context(), id)
+	if err != nil {
+		writeServiceError(c, err)
 		return
 	}
 
@@ -201,17 +409,18 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 
 	err := h.service.DeleteTask(c.Request.Context(), id)
 	if err != nil {
-		if err.Error() == models.ErrTaskNotFound {
-			c.JSON(http.StatusNotFound, ErrorResponse{Error: err.Error()})
-			return
-		}
-		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		writeServiceError(c, err)
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
+// statsDeadline is GetTaskStats' own context budget. It runs several
+// sequential Count queries, so it extends past DeadlineMiddleware's
+// request default rather than racing it.
+const statsDeadline = 30 * time.Second
+
 // GetTaskStats godoc
 // @Summary Get task statistics
 // @Description Returns general statistics about tasks
@@ -221,6 +430,9 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /tasks/stats [get]
 func (h *TaskHandler) GetTaskStats(c *gin.Context) {
+	cancel := middleware.SetDeadline(c, statsDeadline)
+	defer cancel()
+
 	stats, err := h.service.GetTaskStats(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Error fetching statistics"})
@@ -229,3 +441,149 @@ func (h *TaskHandler) GetTaskStats(c *gin.Context) {
 
 	c.JSON(http.StatusOK, stats)
 }
+
+// BulkFailureResponse is one item's failure in a batch response.
+type BulkFailureResponse struct {
+	ID    string `json:"id"`
+	Error string `json:"error"`
+}
+
+// BulkResponse is the partial-success body returned by every /tasks/batch*
+// endpoint: some items may have succeeded while others failed, so the
+// response always carries both lists rather than an all-or-nothing status.
+type BulkResponse struct {
+	Succeeded    any                   `json:"succeeded,omitempty"`
+	Failed       []BulkFailureResponse `json:"failed"`
+	Total        int                   `json:"total"`
+	SucceedCount int                   `json:"succeedCount"`
+	FailedCount  int                   `json:"failedCount"`
+}
+
+// newBulkResponse builds a BulkResponse from succeeded and total, converting
+// a []service.BulkFailure into the response's own failure type.
+func newBulkResponse(succeeded any, succeedCount int, failures []service.BulkFailure, total int) BulkResponse {
+	failed := make([]BulkFailureResponse, len(failures))
+	for i, f := range failures {
+		failed[i] = BulkFailureResponse{ID: f.ID, Error: f.Error}
+	}
+	return BulkResponse{
+		Succeeded:    succeeded,
+		Failed:       failed,
+		Total:        total,
+		SucceedCount: succeedCount,
+		FailedCount:  len(failed),
+	}
+}
+
+// checkBatchSize rejects a batch over h.maxBatchSize with a 413, since a
+// request this large is a client sizing mistake rather than something the
+// server should try to chew through.
+func (h *TaskHandler) checkBatchSize(c *gin.Context, n int) bool {
+	if n > h.maxBatchSize {
+		c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+			Error: fmt.Sprintf("batch size %d exceeds max of %d", n, h.maxBatchSize),
+		})
+		return false
+	}
+	return true
+}
+
+// BulkCreateTasks godoc
+// @Summary Create multiple tasks
+// @Description Creates up to the configured max batch size of tasks in one request. Each item succeeds or fails independently; the response always carries both lists.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param tasks body []models.CreateTaskRequest true "Tasks to create"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /tasks/batch [post]
+func (h *TaskHandler) BulkCreateTasks(c *gin.Context) {
+	var reqs []*models.CreateTaskRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid data: " + err.Error()})
+		return
+	}
+
+	if !h.checkBatchSize(c, len(reqs)) {
+		return
+	}
+
+	tasks, failures, err := h.service.BulkCreateTasks(c.Request.Context(), reqs)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newBulkResponse(tasks, len(tasks), failures, len(reqs)))
+}
+
+// bulkIDsRequest is the shared body shape for the toggle and delete batch
+// endpoints, which both take a flat list of task IDs.
+type bulkIDsRequest struct {
+	IDs []string `json:"ids" binding:"required"`
+}
+
+// BulkToggleTasks godoc
+// @Summary Toggle completion status for multiple tasks
+// @Description Toggles up to the configured max batch size of tasks in one request. Each id succeeds or fails independently; the response always carries both lists.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param ids body bulkIDsRequest true "Task IDs to toggle"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /tasks/batch/toggle [patch]
+func (h *TaskHandler) BulkToggleTasks(c *gin.Context) {
+	var req bulkIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid data: " + err.Error()})
+		return
+	}
+
+	if !h.checkBatchSize(c, len(req.IDs)) {
+		return
+	}
+
+	tasks, failures, err := h.service.BulkToggleTasks(c.Request.Context(), req.IDs)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newBulkResponse(tasks, len(tasks), failures, len(req.IDs)))
+}
+
+// BulkDeleteTasks godoc
+// @Summary Delete multiple tasks
+// @Description Deletes up to the configured max batch size of tasks in one request. Each id succeeds or fails independently; the response always carries both lists.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param ids body bulkIDsRequest true "Task IDs to delete"
+// @Success 200 {object} BulkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 413 {object} ErrorResponse
+// @Router /tasks/batch [delete]
+func (h *TaskHandler) BulkDeleteTasks(c *gin.Context) {
+	var req bulkIDsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid data: " + err.Error()})
+		return
+	}
+
+	if !h.checkBatchSize(c, len(req.IDs)) {
+		return
+	}
+
+	failures, err := h.service.BulkDeleteTasks(c.Request.Context(), req.IDs)
+	if err != nil {
+		writeServiceError(c, err)
+		return
+	}
+
+	succeeded := len(req.IDs) - len(failures)
+	c.JSON(http.StatusOK, newBulkResponse(nil, succeeded, failures, len(req.IDs)))
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	"todo-list-golang/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskHistoryHandler serves the scheduler's per-task execution history.
+type TaskHistoryHandler struct {
+	history repository.JobHistoryRepository
+}
+
+// NewTaskHistoryHandler creates a new handler instance.
+func NewTaskHistoryHandler(history repository.JobHistoryRepository) *TaskHistoryHandler {
+	return &TaskHistoryHandler{history: history}
+}
+
+// GetTaskHistory godoc
+// @Summary List a task's scheduled-execution history
+// @Description Returns the JobHistory entries the scheduler recorded for a task, most recent first
+// @Tags tasks
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {array} models.JobHistory
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /tasks/{id}/history [get]
+func (h *TaskHistoryHandler) GetTaskHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	taskID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "invalid task id"})
+		return
+	}
+
+	history, err := h.history.FindByTaskID(c.Request.Context(), taskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "error fetching task history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"strconv"
+
+	"todo-list-golang/internal/changestream"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TaskStreamHandler streams MongoDB change-stream events for the tasks
+// collection as Server-Sent Events.
+type TaskStreamHandler struct {
+	stream *changestream.TaskStream
+}
+
+// NewTaskStreamHandler creates a new task stream handler instance.
+func NewTaskStreamHandler(stream *changestream.TaskStream) *TaskStreamHandler {
+	return &TaskStreamHandler{stream: stream}
+}
+
+// StreamTaskEvents godoc
+// @Summary Stream live task changes
+// @Description Pushes task insert/update/delete events as Server-Sent Events, batched over a short coalescing window. Reconnecting clients can send a Last-Event-ID header (an id from a previously received event) to resume without missing events.
+// @Tags tasks
+// @Produce text/event-stream
+// @Param completed query bool false "Only stream events for tasks matching this completed value"
+// @Param priority query string false "Only stream events for tasks matching this priority"
+// @Param Last-Event-ID header string false "Resume token from a previously received event"
+// @Success 200
+// @Router /tasks/stream [get]
+func (h *TaskStreamHandler) StreamTaskEvents(c *gin.Context) {
+	match := bson.M{}
+	if completedStr := c.Query("completed"); completedStr != "" {
+		if completed, err := strconv.ParseBool(completedStr); err == nil {
+			match["fullDocument.completed"] = completed
+		}
+	}
+	if priority := c.Query("priority"); priority != "" {
+		match["fullDocument.priority"] = priority
+	}
+
+	resumeAfter := c.GetHeader("Last-Event-ID")
+
+	events, unsubscribe := h.stream.Subscribe(match, resumeAfter)
+	defer unsubscribe()
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.Header().Set("Cache-Control", "no-cache")
+	c.Writer.Header().Set("Connection", "keep-alive")
+
+	ctx := c.Request.Context()
+
+	c.Stream(func(w gin.ResponseWriter) bool {
+		select {
+		case batch, ok := <-events:
+			if !ok {
+				return false
+			}
+			for _, event := range batch {
+				c.SSEvent("task", event)
+			}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
@@ -2,28 +2,189 @@
 package middleware
 
 import (
+	"regexp"
+	"strings"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 )
 
-// CORSMiddleware configures CORS to allow requests from the frontend
-func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		origin := c.Request.Header.Get("Origin")
+// CORSConfig configures CORSMiddleware's origin matching and response
+// headers. Build one with NewCORSConfig and CORSConfigOptions. An origin is
+// allowed if AllowAll is set, it's an exact match in Origins, it matches a
+// Wildcards pattern (a single "*" segment, e.g. "https://*.example.com"), it
+// matches a compiled Patterns regexp, or MatchFunc returns true for it.
+// Wildcard/regex/MatchFunc results are cached per origin so a repeat Origin
+// header only pays for matching once.
+type CORSConfig struct {
+	AllowAll         bool
+	AllowCredentials bool
+	ExposeHeaders    string
+
+	origins   []string
+	wildcards []string
+	patterns  []*regexp.Regexp
+	matchFunc func(origin string) bool
+
+	mu    sync.Mutex
+	cache map[string]bool
+}
+
+// CORSConfigOption configures optional matchers and headers on NewCORSConfig.
+type CORSConfigOption func(*CORSConfig)
+
+// WithAllowAll allows every origin. Per the CORS spec this can't be
+// combined with credentials using the "*" shorthand, so once AllowCredentials
+// is also set, CORSMiddleware echoes back the request's own Origin instead.
+func WithAllowAll() CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.AllowAll = true
+	}
+}
+
+// WithOrigins allow-lists exact origin strings, e.g. "https://app.example.com".
+func WithOrigins(origins ...string) CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.origins = append(c.origins, origins...)
+	}
+}
+
+// WithWildcards allow-lists origin patterns containing a single "*"
+// segment, e.g. "https://*.example.com" matches any subdomain of
+// example.com.
+func WithWildcards(patterns ...string) CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.wildcards = append(c.wildcards, patterns...)
+	}
+}
+
+// WithPatterns allow-lists origins matching any of the given compiled
+// regular expressions, for matching rules a literal or wildcard can't
+// express.
+func WithPatterns(patterns ...*regexp.Regexp) CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.patterns = append(c.patterns, patterns...)
+	}
+}
+
+// WithMatchFunc allow-lists origins for which fn returns true, for lookups
+// against an external allow-list or any other logic a literal, wildcard, or
+// regex list can't express.
+func WithMatchFunc(fn func(origin string) bool) CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.matchFunc = fn
+	}
+}
+
+// WithCredentials sets Access-Control-Allow-Credentials: true on allowed
+// responses.
+func WithCredentials() CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.AllowCredentials = true
+	}
+}
+
+// WithExposedHeaders sets Access-Control-Expose-Headers on allowed
+// responses, e.g. "X-Total-Count, Link" so browser JS can read headers the
+// browser hides by default.
+func WithExposedHeaders(headers string) CORSConfigOption {
+	return func(c *CORSConfig) {
+		c.ExposeHeaders = headers
+	}
+}
+
+// NewCORSConfig builds a CORSConfig from opts. With no options it allows no
+// origins.
+func NewCORSConfig(opts ...CORSConfigOption) *CORSConfig {
+	c := &CORSConfig{cache: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// allows reports whether origin is allowed by c, caching the outcome of the
+// wildcard/regex/MatchFunc checks (the exact-match list is cheap enough to
+// re-scan every time).
+func (c *CORSConfig) allows(origin string) bool {
+	if c.AllowAll {
+		return true
+	}
+	if origin == "" {
+		return false
+	}
 
-		// Check if the origin is in the allowed list
-		allowed := false
-		for _, allowedOrigin := range allowedOrigins {
-			if allowedOrigin == "*" || allowedOrigin == origin {
+	for _, o := range c.origins {
+		if o == origin {
+			return true
+		}
+	}
+
+	c.mu.Lock()
+	if allowed, ok := c.cache[origin]; ok {
+		c.mu.Unlock()
+		return allowed
+	}
+	c.mu.Unlock()
+
+	allowed := false
+	for _, pattern := range c.wildcards {
+		if matchWildcard(pattern, origin) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		for _, re := range c.patterns {
+			if re.MatchString(origin) {
 				allowed = true
 				break
 			}
 		}
+	}
+	if !allowed && c.matchFunc != nil {
+		allowed = c.matchFunc(origin)
+	}
 
-		if allowed {
-			if origin != "" {
-				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
-			} else if len(allowedOrigins) > 0 && allowedOrigins[0] == "*" {
+	c.mu.Lock()
+	c.cache[origin] = allowed
+	c.mu.Unlock()
+
+	return allowed
+}
+
+// matchWildcard reports whether origin matches pattern, which contains
+// exactly one "*" standing in for a non-empty run of characters (e.g.
+// "https://*.example.com" matches "https://api.example.com" but not
+// "https://example.com").
+func matchWildcard(pattern, origin string) bool {
+	star := strings.IndexByte(pattern, '*')
+	if star < 0 {
+		return pattern == origin
+	}
+	prefix, suffix := pattern[:star], pattern[star+1:]
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) &&
+		strings.HasSuffix(origin, suffix)
+}
+
+// CORSMiddleware configures CORS using cfg's origin matching and headers.
+func CORSMiddleware(cfg *CORSConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		origin := c.Request.Header.Get("Origin")
+
+		if cfg.allows(origin) {
+			if cfg.AllowAll && !cfg.AllowCredentials {
 				c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+			} else if origin != "" {
+				c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
+				c.Writer.Header().Set("Vary", "Origin")
+			}
+			if cfg.AllowCredentials {
+				c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if cfg.ExposeHeaders != "" {
+				c.Writer.Header().Set("Access-Control-Expose-Headers", cfg.ExposeHeaders)
 			}
 		}
 
@@ -41,19 +202,8 @@ func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	}
 }
 
-// SimpleCORS is a simpler CORS middleware that allows all origins
-// Useful only for development
+// SimpleCORS is a CORSMiddleware that allows all origins. Useful only for
+// development.
 func SimpleCORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	}
+	return CORSMiddleware(NewCORSConfig(WithAllowAll()))
 }
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestTimeoutHeader lets a caller request a shorter (or longer, up to
+// maxTimeout) deadline than the route's default, as a whole number of
+// seconds.
+const requestTimeoutHeader = "X-Request-Timeout"
+
+// DeadlineMiddleware bounds each request's context with a deadline, so
+// MongoDB operations (and anything else selecting on ctx.Done()) are
+// canceled once the client disconnects or the deadline lapses instead of
+// running to completion for nobody. defaultTimeout applies unless
+// perRouteOverrides has an entry for c.FullPath() - used for routes like
+// long-lived SSE streams that need far more than the default. Either can
+// be overridden per request by an inbound X-Request-Timeout header
+// (seconds), which is clamped to maxTimeout so a client can't use it to
+// bypass a route's intended limit. A handler that times out gets
+// translated to a 504 with a structured error body, unless the handler
+// already wrote a response before the deadline fired.
+func DeadlineMiddleware(defaultTimeout time.Duration, perRouteOverrides map[string]time.Duration, maxTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := defaultTimeout
+		if override, ok := perRouteOverrides[c.FullPath()]; ok {
+			timeout = override
+		}
+
+		if header := c.GetHeader(requestTimeoutHeader); header != "" {
+			if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+				requested := time.Duration(seconds) * time.Second
+				if requested > maxTimeout {
+					requested = maxTimeout
+				}
+				timeout = requested
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{"error": "request exceeded its deadline"})
+		}
+	}
+}
+
+// SetDeadline replaces c's request context with one deadlined timeout from
+// now, detached from the deadline DeadlineMiddleware already set (though
+// not from its values, like the request ID), for handlers that know up
+// front they need a different budget than the route default - stats
+// aggregation, bulk delete - similar to how gonet's deadlineTimer lets a
+// caller reset a per-operation timer rather than being stuck with the one
+// set at accept time. Callers must defer the returned cancel.
+func SetDeadline(c *gin.Context, timeout time.Duration) context.CancelFunc {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), timeout)
+	c.Request = c.Request.WithContext(ctx)
+	return cancel
+}
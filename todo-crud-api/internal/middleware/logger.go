@@ -1,51 +1,68 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"time"
 
+	"todo-list-golang/internal/audit"
+	"todo-list-golang/internal/requestid"
+
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 )
 
-// Logger is a custom middleware for request logging
-func Logger() gin.HandlerFunc {
+const requestIDHeader = "X-Request-ID"
+
+// RequestID assigns each request a UUIDv7 correlation ID (honoring an
+// inbound X-Request-ID if the caller already has one), stashes it on the
+// request context so the service and repository layers can log it
+// alongside their own work, echoes it back in the response header, and
+// opens an OpenTelemetry span carrying it as an attribute.
+func RequestID() gin.HandlerFunc {
+	tracer := otel.Tracer("todo-crud-api")
+
 	return func(c *gin.Context) {
-		// Start time
-		startTime := time.Now()
+		id := c.GetHeader(requestIDHeader)
+		if id == "" {
+			id = requestid.NewV7()
+		}
 
-		// Process the request
-		c.Next()
+		ctx, span := tracer.Start(c.Request.Context(), c.Request.Method+" "+c.FullPath())
+		span.SetAttributes(attribute.String("request_id", id))
+		defer span.End()
 
-		// End time
-		endTime := time.Now()
-		latency := endTime.Sub(startTime)
-
-		// Request information
-		statusCode := c.Writer.Status()
-		clientIP := c.ClientIP()
-		method := c.Request.Method
-		path := c.Request.URL.Path
-		errorMessage := c.Errors.ByType(gin.ErrorTypePrivate).String()
-
-		// Formatted log
-		log.Printf("[%s] %s %s %d %s %s",
-			method,
-			path,
-			clientIP,
-			statusCode,
-			latency,
-			errorMessage,
-		)
+		ctx = requestid.WithRequestID(ctx, id)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Writer.Header().Set(requestIDHeader, id)
+		c.Set("RequestID", id)
+		c.Next()
 	}
 }
 
-// RequestIDMiddleware adds a unique ID for each request
-func RequestIDMiddleware() gin.HandlerFunc {
+// Logger emits one structured JSON log line per request via log/slog,
+// correlating it to the request's ID, the audit trace ID (if the caller
+// sent one), and MongoDB command logs emitted while handling it.
+func Logger() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Generate a unique ID for the request
-		requestID := time.Now().UnixNano()
-		c.Set("RequestID", requestID)
-		c.Writer.Header().Set("X-Request-ID", string(rune(requestID)))
+		start := time.Now()
+
 		c.Next()
+
+		latency := time.Since(start)
+		ctx := c.Request.Context()
+
+		slog.Info("http_request",
+			"request_id", requestid.FromContext(ctx),
+			"trace_id", audit.TraceID(ctx),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency_ms", latency.Milliseconds(),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+			"error", c.Errors.ByType(gin.ErrorTypePrivate).String(),
+		)
 	}
 }
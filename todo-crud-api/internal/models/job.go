@@ -0,0 +1,93 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrJobNotFound is returned when a job lookup or transition targets a job
+// that does not exist in the collection.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStatus represents the lifecycle state of a background job/execution.
+type JobStatus string
+
+// Job and execution statuses.
+const (
+	JobStatusPending   JobStatus = "pending"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+	JobStatusCanceled  JobStatus = "canceled"
+)
+
+// Job kinds known to the built-in worker handlers.
+const (
+	JobKindReminder       = "reminder"
+	JobKindRecurringCheck = "recurring-check"
+	JobKindBulkImport     = "bulk-import"
+	JobKindTaskAudit      = "task-audit"
+	// JobKindScheduleCreate is the JobHistory.Kind recorded by
+	// internal/scheduler's ScheduleWorker each time it creates a new Task
+	// from a Schedule's TaskTemplate.
+	JobKindScheduleCreate = "schedule-create"
+)
+
+// Job represents a unit of work enqueued for background processing.
+// A Job always has exactly one Execution tracking its attempts.
+type Job struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Kind            string             `json:"kind" bson:"kind"`
+	Payload         bson.M             `json:"payload,omitempty" bson:"payload,omitempty"`
+	Status          JobStatus          `json:"status" bson:"status"`
+	Retries         int                `json:"retries" bson:"retries"`
+	WorkerID        string             `json:"workerId,omitempty" bson:"workerId,omitempty"`
+	LeaseExpiresAt  *time.Time         `json:"leaseExpiresAt,omitempty" bson:"leaseExpiresAt,omitempty"`
+	LastHeartbeatAt *time.Time         `json:"lastHeartbeatAt,omitempty" bson:"lastHeartbeatAt,omitempty"`
+	Error           string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt       time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Execution records a single run of a Job for observability and history.
+// TaskID is optional: it is only set for jobs that operate on a specific task.
+type Execution struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	JobID     primitive.ObjectID `json:"jobId" bson:"jobId"`
+	TaskID    primitive.ObjectID `json:"taskId,omitempty" bson:"taskId,omitempty"`
+	Kind      string             `json:"kind" bson:"kind"`
+	Status    JobStatus          `json:"status" bson:"status"`
+	Retries   int                `json:"retries" bson:"retries"`
+	StartedAt time.Time          `json:"startedAt" bson:"startedAt"`
+	EndedAt   *time.Time         `json:"endedAt,omitempty" bson:"endedAt,omitempty"`
+	Error     string             `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// JobHistory records one run of a scheduled Task by the internal/scheduler
+// Worker: when it ran, how it went, and why, if it failed. Unlike Execution
+// (which tracks attempts of an enqueued Job), a JobHistory entry is written
+// directly against a Task's Schedule/NextRunAt fields.
+type JobHistory struct {
+	ID     primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TaskID primitive.ObjectID `json:"taskId" bson:"taskId"`
+	Kind   string             `json:"kind" bson:"kind"`
+	Status JobStatus          `json:"status" bson:"status"`
+	// ScheduleID is set when this entry records a Schedule's execution
+	// (internal/scheduler's ScheduleWorker) rather than a Task's own
+	// Schedule field, so GET /schedules/{id}/executions can look it up.
+	ScheduleID primitive.ObjectID `json:"scheduleId,omitempty" bson:"scheduleId,omitempty"`
+	StartedAt  time.Time          `json:"startedAt" bson:"startedAt"`
+	EndedAt    *time.Time         `json:"endedAt,omitempty" bson:"endedAt,omitempty"`
+	Error      string             `json:"error,omitempty" bson:"error,omitempty"`
+}
+
+// JobEvent is broadcast to subscribers whenever a job's status changes.
+type JobEvent struct {
+	JobID  primitive.ObjectID `json:"jobId"`
+	Kind   string             `json:"kind"`
+	Status JobStatus          `json:"status"`
+	At     time.Time          `json:"at"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TaskTemplate is the set of Task fields a Schedule uses to create a new
+// Task each time its Cron expression fires. It mirrors CreateTaskRequest
+// rather than Task itself, since a template describes what to create, not a
+// document's full lifecycle state.
+type TaskTemplate struct {
+	Name        string `json:"name" bson:"name" binding:"required,min=1,max=200"`
+	Description string `json:"description" bson:"description" binding:"max=1000"`
+	Priority    string `json:"priority" bson:"priority" binding:"omitempty,oneof=low medium high"`
+}
+
+// ToTask builds a new Task from the template, tagged with scheduleID so a
+// RetentionPolicy can later find every Task this Schedule created.
+func (t *TaskTemplate) ToTask(scheduleID primitive.ObjectID) *Task {
+	priority := t.Priority
+	if priority == "" {
+		priority = "medium"
+	}
+
+	now := time.Now()
+	return &Task{
+		Name:        t.Name,
+		Description: t.Description,
+		Status:      StatusActive,
+		Priority:    priority,
+		ScheduleID:  scheduleID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Version:     1,
+	}
+}
+
+// RetentionPolicy auto-purges Tasks a Schedule created once they've been
+// StatusCompleted for longer than After, so a recurring schedule (e.g. a
+// daily reminder) doesn't accumulate completed Tasks forever.
+type RetentionPolicy struct {
+	After time.Duration `json:"after" bson:"after"`
+}
+
+// Schedule defines a recurring task-creation rule: every occurrence of Cron,
+// internal/scheduler's ScheduleWorker creates a new Task from Template.
+// Unlike Task.Schedule (which re-runs a single existing Task via
+// scheduler.Worker), a Schedule creates a brand new Task document each time
+// - the "auto-create recurring todo items" case.
+type Schedule struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Cron      string             `json:"cron" bson:"cron" binding:"required"`
+	Template  TaskTemplate       `json:"template" bson:"template" binding:"required"`
+	Retention *RetentionPolicy   `json:"retention,omitempty" bson:"retention,omitempty"`
+	NextRunAt time.Time          `json:"nextRunAt" bson:"nextRunAt"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+
+	// LockedUntil is the lease a ScheduleWorker holds while creating this
+	// occurrence's Task, the same pattern Task.LockedUntil uses for
+	// scheduler.Worker, so multiple app instances can run the ScheduleWorker
+	// without double-creating a Task for the same occurrence.
+	LockedUntil *time.Time `json:"-" bson:"lockedUntil,omitempty"`
+}
+
+// ExecutionCounts aggregates a Schedule's JobHistory entries by status, for
+// GET /schedules/{id}/executions.
+type ExecutionCounts struct {
+	Total      int64 `json:"total"`
+	Succeeded  int64 `json:"succeeded"`
+	Failed     int64 `json:"failed"`
+	InProgress int64 `json:"inProgress"`
+}
+
+// CreateScheduleRequest is the payload for POST /schedules.
+type CreateScheduleRequest struct {
+	Cron      string           `json:"cron" binding:"required"`
+	Template  TaskTemplate     `json:"template" binding:"required"`
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+}
+
+// ToSchedule converts a CreateScheduleRequest to a Schedule. NextRunAt is
+// left zero; the caller computes it once the cron expression is validated.
+func (r *CreateScheduleRequest) ToSchedule() *Schedule {
+	now := time.Now()
+	return &Schedule{
+		Cron:      r.Cron,
+		Template:  r.Template,
+		Retention: r.Retention,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -2,23 +2,137 @@ package models
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Status represents the lifecycle state of a Task.
+type Status string
+
+// Task lifecycle states.
+//
+// Allowed transitions:
+//
+//	pending   -> active
+//	active    -> paused | completed | canceled
+//	paused    -> active
+//	completed -> (terminal)
+//	canceled  -> (terminal)
+//
+// Pause and Resume enforce these edges. The legacy completion toggle
+// (service.ToggleTaskCompletion) and UpdateTaskRequest.Completed set Status
+// directly without consulting them: they move any other status to
+// completed, and a completed task back to active.
+const (
+	StatusPending   Status = "pending"
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCompleted Status = "completed"
+	StatusCanceled  Status = "canceled"
+)
+
+// ErrVersionConflict is returned when an update targets a document whose
+// stored version no longer matches the expected version, meaning another
+// writer updated it first.
+var ErrVersionConflict = errors.New("task version conflict")
+
+// ErrInvalidTransition is returned when a status transition is not allowed
+// by the Task state machine.
+type ErrInvalidTransition struct {
+	From Status
+	To   Status
+}
+
+func (e *ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("invalid status transition from %q to %q", e.From, e.To)
+}
+
+// validTransitions enumerates the allowed Status state machine edges.
+var validTransitions = map[Status]map[Status]bool{
+	StatusPending:   {StatusActive: true},
+	StatusActive:    {StatusPaused: true, StatusCompleted: true, StatusCanceled: true},
+	StatusPaused:    {StatusActive: true},
+	StatusCompleted: {},
+	StatusCanceled:  {},
+}
+
+// CanTransition reports whether moving from one status to another is legal.
+func CanTransition(from, to Status) bool {
+	return validTransitions[from][to]
+}
+
 // Task represents a task in the system
 type Task struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name        string             `json:"name" bson:"name" binding:"required"`
 	Description string             `json:"description" bson:"description"`
-	Completed   bool               `json:"completed" bson:"completed"`
-	Priority    string             `json:"priority" bson:"priority"` // low, medium, high
-	DueDate     *time.Time         `json:"dueDate,omitempty" bson:"dueDate,omitempty"`
-	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
-	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
-	Version     int                `json:"version" bson:"_v"` // Document version for tracking changes
+	// Completed is kept for backwards compatibility with existing clients
+	// and is derived from Status; Status is the source of truth going forward.
+	Completed   bool       `json:"completed" bson:"completed"`
+	Status      Status     `json:"status" bson:"status,omitempty"`
+	PausedAt    *time.Time `json:"pausedAt,omitempty" bson:"pausedAt,omitempty"`
+	PauseReason string     `json:"pauseReason,omitempty" bson:"pauseReason,omitempty"`
+	Priority    string     `json:"priority" bson:"priority"` // low, medium, high
+	DueDate     *time.Time `json:"dueDate,omitempty" bson:"dueDate,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt" bson:"updatedAt"`
+	Version     int        `json:"version" bson:"_v"` // Document version for tracking changes
+
+	// Audit trail, following the shape of Forgejo's admin.Task: DoerID is
+	// whoever performed the most recent mutation, OwnerID is fixed at
+	// creation, and TraceID correlates this document's state with the
+	// audit_log entries recorded for the request that produced it.
+	DoerID  string `json:"doerId,omitempty" bson:"doerId,omitempty"`
+	OwnerID string `json:"ownerId,omitempty" bson:"ownerId,omitempty"`
+	TraceID string `json:"traceId,omitempty" bson:"traceId,omitempty"`
+
+	// Scheduling, read by the internal/scheduler Worker: a Task with a
+	// non-empty Schedule (a standard 5-field cron expression) is executed by
+	// the registered TaskHandler for Kind once NextRunAt elapses. LockedUntil
+	// is the lease a worker holds while running the task, the same pattern
+	// Job uses for LeaseExpiresAt, so multiple app instances can run the
+	// scheduler without double-executing a task.
+	Schedule    string     `json:"schedule,omitempty" bson:"schedule,omitempty"`
+	Kind        string     `json:"kind,omitempty" bson:"kind,omitempty"`
+	NextRunAt   *time.Time `json:"nextRunAt,omitempty" bson:"nextRunAt,omitempty"`
+	LockedUntil *time.Time `json:"-" bson:"lockedUntil,omitempty"`
+
+	// ScheduleID is set on a Task created by a Schedule (internal/scheduler's
+	// ScheduleWorker) from its TaskTemplate, so the Schedule's RetentionPolicy
+	// can find every Task it created. Empty for tasks created directly via
+	// CreateTaskRequest.
+	ScheduleID primitive.ObjectID `json:"scheduleId,omitempty" bson:"scheduleId,omitempty"`
+}
+
+// EnsureStatus backfills Status on documents written before the status
+// field existed: completed=true maps to StatusCompleted, completed=false
+// maps to StatusActive. Call this after decoding a Task read from storage.
+func (t *Task) EnsureStatus() {
+	if t.Status != "" {
+		return
+	}
+	if t.Completed {
+		t.Status = StatusCompleted
+	} else {
+		t.Status = StatusActive
+	}
+}
+
+// syncCompleted keeps the legacy Completed bool in lockstep with Status so
+// old clients reading the JSON response keep working unchanged.
+func (t *Task) syncCompleted() {
+	t.Completed = t.Status == StatusCompleted
+}
+
+// ETag returns the task's current version formatted for use as an HTTP
+// ETag / If-Match value, so clients can round-trip it back on a later
+// update without reaching into the JSON body.
+func (t *Task) ETag() string {
+	return strconv.Itoa(t.Version)
 }
 
 // CreateTaskRequest represents the payload to create a new task
@@ -36,12 +150,85 @@ type UpdateTaskRequest struct {
 	Completed   *bool      `json:"completed"`
 	Priority    *string    `json:"priority" binding:"omitempty,oneof=low medium high"`
 	DueDate     *time.Time `json:"dueDate"`
+	// ExpectedVersion, when set, is the version the client last saw (from
+	// Task.ETag, via the JSON body or the If-Match header). If the stored
+	// task is at a different version, the update is rejected with
+	// ErrVersionConflict instead of being retried against whatever is
+	// latest: the client asked to detect that it edited a stale copy.
+	ExpectedVersion *int `json:"expectedVersion,omitempty"`
 }
 
 // TaskFilter represents filters to search for tasks
 type TaskFilter struct {
 	Completed *bool
 	Priority  string
+	Status    Status
+
+	// Limit caps how many tasks FindAll returns, enabling cursor
+	// pagination. Zero means no limit, so existing callers that don't set
+	// it keep getting every matching task.
+	Limit int
+	// Cursor is the opaque (sort field value, _id) position FindAll
+	// resumes after, as returned by a previous call's next cursor. Must be
+	// a cursor produced for the same Sort, since it's decoded against it.
+	Cursor string
+	// Sort selects which field FindAll orders by, optionally prefixed with
+	// "-" for descending (e.g. "-dueDate"). Empty means the default,
+	// "-createdAt". See AllowedSortFields for the allow-list.
+	Sort string
+	// Fields restricts which document fields are returned. Only the Mongo
+	// backend honors it (via SetProjection); other backends ignore it.
+	Fields []string
+}
+
+// AllowedSortFields are the fields GetAllTasks may sort by, each also
+// accepted with a leading "-" for descending order.
+var AllowedSortFields = map[string]bool{
+	"createdAt": true,
+	"dueDate":   true,
+	"priority":  true,
+}
+
+// ParseSort splits a sort query param like "-dueDate" into its field name
+// and direction. An empty sort defaults to "createdAt" descending. ok is
+// false if field isn't in AllowedSortFields.
+func ParseSort(sort string) (field string, desc bool, ok bool) {
+	if sort == "" {
+		return "createdAt", true, true
+	}
+	desc = strings.HasPrefix(sort, "-")
+	field = strings.TrimPrefix(sort, "-")
+	return field, desc, AllowedSortFields[field]
+}
+
+// PriorityRank maps a priority string to a numeric rank so "priority" can
+// be used as a sort field like any other: low < medium < high.
+func PriorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 2
+	case "low":
+		return 0
+	default:
+		return 1 // medium, and anything unrecognized
+	}
+}
+
+// Pagination carries GetAllTasks' page-based request controls and, once
+// the service has run, the bookkeeping its handler needs to build
+// X-Total-Count and Link response headers. PageSize caps how many tasks
+// GetAllTasks returns; Page only drives that bookkeeping and does not
+// select which tasks come back - the repository itself still walks forward
+// by an indexed cursor rather than a skip.
+type Pagination struct {
+	Page     int
+	PageSize int
+	Sort     string
+	Cursor   string
+
+	// TotalCount and NextCursor are filled in by GetAllTasks.
+	TotalCount int64
+	NextCursor string
 }
 
 // Validate validates the task data
@@ -91,6 +278,7 @@ func (r *CreateTaskRequest) ToTask() *Task {
 		Name:        strings.TrimSpace(r.Name),
 		Description: strings.TrimSpace(r.Description),
 		Completed:   false,
+		Status:      StatusActive,
 		Priority:    strings.ToLower(priority),
 		DueDate:     r.DueDate,
 		CreatedAt:   now,
@@ -110,7 +298,12 @@ func (r *UpdateTaskRequest) ApplyUpdates(task *Task) {
 	}
 
 	if r.Completed != nil {
-		task.Completed = *r.Completed
+		if *r.Completed {
+			task.Status = StatusCompleted
+		} else if task.Status == StatusCompleted {
+			task.Status = StatusActive
+		}
+		task.syncCompleted()
 	}
 
 	if r.Priority != nil {
@@ -128,5 +321,36 @@ func (r *UpdateTaskRequest) ApplyUpdates(task *Task) {
 // HasUpdates checks if the UpdateTaskRequest has any updates
 func (r *UpdateTaskRequest) HasUpdates() bool {
 	return r.Name != nil || r.Description != nil || r.Completed != nil ||
-	       r.Priority != nil || r.DueDate != nil
+		r.Priority != nil || r.DueDate != nil
+}
+
+// Pause transitions the task to StatusPaused, recording when and why.
+// It returns *ErrInvalidTransition if the task isn't currently active.
+func (t *Task) Pause(reason string) error {
+	if !CanTransition(t.Status, StatusPaused) {
+		return &ErrInvalidTransition{From: t.Status, To: StatusPaused}
+	}
+
+	now := time.Now()
+	t.Status = StatusPaused
+	t.PausedAt = &now
+	t.PauseReason = reason
+	t.UpdatedAt = now
+	t.Version++
+	return nil
+}
+
+// Resume transitions the task back to StatusActive, clearing the pause fields.
+// It returns *ErrInvalidTransition if the task isn't currently paused.
+func (t *Task) Resume() error {
+	if t.Status != StatusPaused || !CanTransition(t.Status, StatusActive) {
+		return &ErrInvalidTransition{From: t.Status, To: StatusActive}
+	}
+
+	t.Status = StatusActive
+	t.PausedAt = nil
+	t.PauseReason = ""
+	t.UpdatedAt = time.Now()
+	t.Version++
+	return nil
 }
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -208,6 +209,69 @@ func TestUpdateTaskRequestHasUpdates(t *testing.T) {
 	}
 }
 
+func TestTaskPauseResume(t *testing.T) {
+	task := &Task{Status: StatusActive}
+
+	if err := task.Pause("waiting on review"); err != nil {
+		t.Fatalf("Pause() unexpected error = %v", err)
+	}
+	if task.Status != StatusPaused {
+		t.Errorf("expected status %q, got %q", StatusPaused, task.Status)
+	}
+	if task.PausedAt == nil {
+		t.Error("expected PausedAt to be set")
+	}
+	if task.PauseReason != "waiting on review" {
+		t.Errorf("expected pause reason to be recorded, got %q", task.PauseReason)
+	}
+
+	if err := task.Resume(); err != nil {
+		t.Fatalf("Resume() unexpected error = %v", err)
+	}
+	if task.Status != StatusActive {
+		t.Errorf("expected status %q, got %q", StatusActive, task.Status)
+	}
+	if task.PausedAt != nil {
+		t.Error("expected PausedAt to be cleared")
+	}
+}
+
+func TestTaskPauseInvalidTransition(t *testing.T) {
+	task := &Task{Status: StatusCompleted}
+
+	err := task.Pause("")
+	if err == nil {
+		t.Fatal("expected an error pausing a completed task")
+	}
+
+	var transitionErr *ErrInvalidTransition
+	if !errors.As(err, &transitionErr) {
+		t.Errorf("expected *ErrInvalidTransition, got %T", err)
+	}
+}
+
+func TestTaskEnsureStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		task      Task
+		wantAfter Status
+	}{
+		{name: "legacy completed", task: Task{Completed: true}, wantAfter: StatusCompleted},
+		{name: "legacy pending", task: Task{Completed: false}, wantAfter: StatusActive},
+		{name: "status already set", task: Task{Completed: false, Status: StatusPaused}, wantAfter: StatusPaused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			task := tt.task
+			task.EnsureStatus()
+			if task.Status != tt.wantAfter {
+				t.Errorf("EnsureStatus() = %q, want %q", task.Status, tt.wantAfter)
+			}
+		})
+	}
+}
+
 // Helper functions for tests
 func stringPtr(s string) *string {
 	return &s
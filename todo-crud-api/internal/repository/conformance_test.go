@@ -0,0 +1,338 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// taskRepositoryConformance exercises the behavior every TaskRepository
+// implementation must share. The Mongo, Postgres, and in-memory backends
+// all run this same suite so that swapping the storage driver can't
+// silently change semantics. newRepo must return a repository backed by
+// empty storage.
+func taskRepositoryConformance(t *testing.T, newRepo func() TaskRepository) {
+	t.Helper()
+
+	t.Run("CreateAndFindByID", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		task := &models.Task{Name: "Write the report", Priority: "medium", Version: 1}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if task.ID.IsZero() {
+			t.Fatal("Create() left task.ID zero")
+		}
+
+		found, err := repo.FindByID(ctx, task.ID)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found.Name != task.Name {
+			t.Errorf("FindByID() Name = %q, want %q", found.Name, task.Name)
+		}
+	})
+
+	t.Run("FindByIDMissingReturnsErrTaskNotFound", func(t *testing.T) {
+		repo := newRepo()
+		_, err := repo.FindByID(context.Background(), primitive.NewObjectID())
+		if err == nil || err.Error() != models.ErrTaskNotFound {
+			t.Fatalf("FindByID() error = %v, want %q", err, models.ErrTaskNotFound)
+		}
+	})
+
+	t.Run("UpdateAppliesOnMatchingVersion", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		task := &models.Task{Name: "Original", Priority: "low", Version: 1}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		updated := *task
+		updated.Name = "Renamed"
+		if err := repo.Update(ctx, task.ID, &updated, task.Version); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+
+		found, err := repo.FindByID(ctx, task.ID)
+		if err != nil {
+			t.Fatalf("FindByID() error = %v", err)
+		}
+		if found.Name != "Renamed" {
+			t.Errorf("FindByID() Name = %q, want %q", found.Name, "Renamed")
+		}
+	})
+
+	t.Run("UpdateRejectsStaleVersion", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		task := &models.Task{Name: "Original", Priority: "low", Version: 1}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		updated := *task
+		updated.Name = "Renamed"
+		err := repo.Update(ctx, task.ID, &updated, task.Version+1)
+		if !errors.Is(err, models.ErrVersionConflict) {
+			t.Fatalf("Update() error = %v, want ErrVersionConflict", err)
+		}
+	})
+
+	t.Run("UpdateMissingReturnsErrTaskNotFound", func(t *testing.T) {
+		repo := newRepo()
+		err := repo.Update(context.Background(), primitive.NewObjectID(), &models.Task{Name: "x"}, 1)
+		if err == nil || err.Error() != models.ErrTaskNotFound {
+			t.Fatalf("Update() error = %v, want %q", err, models.ErrTaskNotFound)
+		}
+	})
+
+	t.Run("DeleteRemovesTask", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		task := &models.Task{Name: "Disposable", Priority: "low", Version: 1}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.Delete(ctx, task.ID); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+		if _, err := repo.FindByID(ctx, task.ID); err == nil {
+			t.Fatal("FindByID() after Delete() returned no error")
+		}
+	})
+
+	t.Run("FindAllFiltersByCompletedAndPriority", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		active := &models.Task{Name: "Active task", Priority: "high", Status: models.StatusActive, Version: 1}
+		done := &models.Task{Name: "Done task", Priority: "low", Status: models.StatusCompleted, Completed: true, Version: 1}
+		if err := repo.Create(ctx, active); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if err := repo.Create(ctx, done); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+
+		completed := true
+		tasks, _, err := repo.FindAll(ctx, &models.TaskFilter{Completed: &completed})
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].ID != done.ID {
+			t.Fatalf("FindAll(completed=true) = %v, want only %v", tasks, done.ID)
+		}
+
+		tasks, _, err = repo.FindAll(ctx, &models.TaskFilter{Priority: "high"})
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(tasks) != 1 || tasks[0].ID != active.ID {
+			t.Fatalf("FindAll(priority=high) = %v, want only %v", tasks, active.ID)
+		}
+	})
+
+	t.Run("FindAllPaginatesWithCursor", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			task := &models.Task{Name: "Task", Version: 1}
+			if err := repo.Create(ctx, task); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		firstPage, nextCursor, err := repo.FindAll(ctx, &models.TaskFilter{Limit: 2})
+		if err != nil {
+			t.Fatalf("FindAll() error = %v", err)
+		}
+		if len(firstPage) != 2 {
+			t.Fatalf("FindAll(limit=2) returned %d tasks, want 2", len(firstPage))
+		}
+		if nextCursor == "" {
+			t.Fatal("FindAll(limit=2) nextCursor = \"\", want non-empty")
+		}
+
+		secondPage, nextCursor, err := repo.FindAll(ctx, &models.TaskFilter{Limit: 2, Cursor: nextCursor})
+		if err != nil {
+			t.Fatalf("FindAll() with cursor error = %v", err)
+		}
+		if len(secondPage) != 1 {
+			t.Fatalf("FindAll() with cursor returned %d tasks, want 1", len(secondPage))
+		}
+		if nextCursor != "" {
+			t.Fatalf("FindAll() last page nextCursor = %q, want \"\"", nextCursor)
+		}
+	})
+
+	t.Run("FindAllPaginatesAcrossTiedSortValues", func(t *testing.T) {
+		// Regression test: a keyset cursor that ANDs the sort key with _id
+		// instead of OR-ing them drops every row sharing the cursor's sort
+		// value but with an out-of-range _id.
+		repo := newRepo()
+		ctx := context.Background()
+
+		dueDate := time.Now().Add(24 * time.Hour)
+		ids := map[string]bool{}
+		for i := 0; i < 4; i++ {
+			task := &models.Task{Name: "Task", Priority: "low", DueDate: &dueDate, Version: 1}
+			if err := repo.Create(ctx, task); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			ids[task.ID.Hex()] = true
+		}
+
+		seen := map[string]bool{}
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			tasks, next, err := repo.FindAll(ctx, &models.TaskFilter{Sort: "dueDate", Limit: 2, Cursor: cursor})
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+			for _, task := range tasks {
+				seen[task.ID.Hex()] = true
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		if len(seen) != len(ids) {
+			t.Fatalf("FindAll(sort=dueDate) paginated over tied due dates returned %d distinct tasks, want %d", len(seen), len(ids))
+		}
+	})
+
+	t.Run("FindAllPaginatesByPriorityAcrossTiedRanks", func(t *testing.T) {
+		// Regression test: priority has only 3 distinct ranks, so a keyset
+		// cursor that ANDs _priorityRank with _id instead of OR-ing them
+		// drops every task sharing the cursor's rank but sorting after it
+		// by _id.
+		repo := newRepo()
+		ctx := context.Background()
+
+		ids := map[string]bool{}
+		for i := 0; i < 4; i++ {
+			task := &models.Task{Name: "Task", Priority: "high", Version: 1}
+			if err := repo.Create(ctx, task); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			ids[task.ID.Hex()] = true
+		}
+
+		seen := map[string]bool{}
+		cursor := ""
+		for page := 0; page < 10; page++ {
+			tasks, next, err := repo.FindAll(ctx, &models.TaskFilter{Sort: "priority", Limit: 2, Cursor: cursor})
+			if err != nil {
+				t.Fatalf("FindAll() error = %v", err)
+			}
+			for _, task := range tasks {
+				seen[task.ID.Hex()] = true
+			}
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+
+		if len(seen) != len(ids) {
+			t.Fatalf("FindAll(sort=priority) paginated over tied ranks returned %d distinct tasks, want %d", len(seen), len(ids))
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		for i := 0; i < 3; i++ {
+			if err := repo.Create(ctx, &models.Task{Name: "task", Priority: "low", Version: 1}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+		}
+
+		count, err := repo.Count(ctx, nil)
+		if err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+		if count != 3 {
+			t.Errorf("Count() = %d, want 3", count)
+		}
+	})
+
+	t.Run("BulkCreateReportsOneResultPerTask", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		tasks := []*models.Task{
+			{Name: "first", Priority: "low", Version: 1},
+			{Name: "second", Priority: "high", Version: 1},
+		}
+		results, err := repo.BulkCreate(ctx, tasks)
+		if err != nil {
+			t.Fatalf("BulkCreate() error = %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("BulkCreate() returned %d results, want 2", len(results))
+		}
+		for i, result := range results {
+			if result.Err != nil {
+				t.Errorf("BulkCreate() results[%d].Err = %v, want nil", i, result.Err)
+			}
+			if result.ID != tasks[i].ID.Hex() {
+				t.Errorf("BulkCreate() results[%d].ID = %q, want %q", i, result.ID, tasks[i].ID.Hex())
+			}
+		}
+
+		count, err := repo.Count(ctx, nil)
+		if err != nil {
+			t.Fatalf("Count() error = %v", err)
+		}
+		if count != 2 {
+			t.Errorf("Count() after BulkCreate() = %d, want 2", count)
+		}
+	})
+
+	t.Run("BulkDeleteReportsNotFoundWithoutFailingOthers", func(t *testing.T) {
+		repo := newRepo()
+		ctx := context.Background()
+
+		task := &models.Task{Name: "keep me gone", Priority: "low", Version: 1}
+		if err := repo.Create(ctx, task); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		missing := primitive.NewObjectID()
+
+		results, err := repo.BulkDelete(ctx, []primitive.ObjectID{task.ID, missing})
+		if err != nil {
+			t.Fatalf("BulkDelete() error = %v", err)
+		}
+		if len(results) != 2 {
+			t.Fatalf("BulkDelete() returned %d results, want 2", len(results))
+		}
+		if results[0].Err != nil {
+			t.Errorf("BulkDelete() results[0].Err = %v, want nil", results[0].Err)
+		}
+		if results[1].Err == nil {
+			t.Error("BulkDelete() results[1].Err = nil, want an error for a missing id")
+		}
+
+		if _, err := repo.FindByID(ctx, task.ID); err == nil {
+			t.Error("FindByID() after BulkDelete() = nil error, want task to be gone")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidCursor indicates a cursor string DecodeCursor couldn't parse,
+// either malformed or produced for a different sort than the one it's
+// being decoded against.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the opaque pagination position FindAll accepts and returns:
+// the sort field (with its "-" prefix, if any) the page was walked by,
+// that field's comparable value at the last document on the page (unix
+// nanoseconds for createdAt/dueDate, models.PriorityRank for priority),
+// and the document's _id as a tiebreaker.
+type Cursor struct {
+	Sort  string             `json:"sort"`
+	Value int64              `json:"value"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+// EncodeCursor returns an opaque, base64-encoded cursor for the given
+// position, suitable for returning to a client and round-tripping back
+// through DecodeCursor on a later call using the same sort.
+func EncodeCursor(sort string, value int64, id primitive.ObjectID) string {
+	data, _ := json.Marshal(Cursor{Sort: sort, Value: value, ID: id})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor reverses EncodeCursor, returning ErrInvalidCursor if s isn't
+// a cursor this package produced.
+func DecodeCursor(s string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return c, nil
+}
+
+// checkCursorSort reports ErrInvalidCursor if cur was produced for a
+// different sort than rawSort: cursorValue's encoding isn't comparable
+// across sort fields, so decoding it against the wrong one would silently
+// misorder the page instead of erroring.
+func checkCursorSort(cur Cursor, rawSort string) error {
+	if cur.Sort != rawSort {
+		return fmt.Errorf("%w: cursor was issued for sort %q, not %q", ErrInvalidCursor, cur.Sort, rawSort)
+	}
+	return nil
+}
+
+// cursorValue returns task's comparable value for sortField, the same
+// representation EncodeCursor/DecodeCursor carry as Cursor.Value.
+func cursorValue(task *models.Task, sortField string) int64 {
+	switch sortField {
+	case "dueDate":
+		if task.DueDate == nil {
+			return 0
+		}
+		return task.DueDate.UnixNano()
+	case "priority":
+		return int64(models.PriorityRank(task.Priority))
+	default:
+		return task.CreatedAt.UnixNano()
+	}
+}
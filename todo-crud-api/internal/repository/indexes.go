@@ -11,60 +11,108 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-// indexModels defines all required indexes for the tasks collection
-// This is the single source of truth for index definitions
-var indexModels = []mongo.IndexModel{
-	// Index on 'completed' field for filtering
+// IndexSpec is a declarative index definition for the tasks collection.
+// ExpireAfter turns it into a TTL index (expiring matching documents
+// ExpireAfter after the value of Keys' date field); PartialFilterExpression
+// restricts it to documents matching that filter, so the two can combine
+// into e.g. a TTL that only reaps completed tasks. Zero values for both
+// leave it a plain index.
+type IndexSpec struct {
+	Name                    string
+	Keys                    bson.D
+	Sparse                  bool
+	ExpireAfter             time.Duration
+	PartialFilterExpression bson.M
+}
+
+// toIndexModel translates an IndexSpec into the mongo.IndexModel the driver
+// expects.
+func (s IndexSpec) toIndexModel() mongo.IndexModel {
+	opts := options.Index().SetName(s.Name)
+	if s.Sparse {
+		opts.SetSparse(true)
+	}
+	if s.ExpireAfter > 0 {
+		opts.SetExpireAfterSeconds(int32(s.ExpireAfter.Seconds()))
+	}
+	if s.PartialFilterExpression != nil {
+		opts.SetPartialFilterExpression(s.PartialFilterExpression)
+	}
+	return mongo.IndexModel{Keys: s.Keys, Options: opts}
+}
+
+// registeredIndexes is the single source of truth for which indexes the
+// tasks collection should have, populated by RegisterIndex. It replaces a
+// single hard-coded package var so the set of indexes can grow without
+// every caller needing to know the literal list.
+var registeredIndexes []IndexSpec
+
+// RegisterIndex adds spec to the set of indexes IndexManager maintains. It's
+// meant to be called from package-level init()s, the same way
+// internal/migration's migrations register themselves.
+func RegisterIndex(spec IndexSpec) {
+	registeredIndexes = append(registeredIndexes, spec)
+}
+
+func init() {
+	// Index on 'completed' field for filtering.
 	// Used in: GET /tasks?completed=true/false
-	{
+	RegisterIndex(IndexSpec{
+		Name: "idx_completed",
 		Keys: bson.D{{Key: "completed", Value: 1}},
-		Options: options.Index().
-			SetName("idx_completed"),
-	},
+	})
 
-	// Index on 'priority' field for filtering
+	// Index on 'priority' field for filtering.
 	// Used in: GET /tasks?priority=high/medium/low
-	{
+	RegisterIndex(IndexSpec{
+		Name: "idx_priority",
 		Keys: bson.D{{Key: "priority", Value: 1}},
-		Options: options.Index().
-			SetName("idx_priority"),
-	},
+	})
 
-	// Compound index on 'completed' and 'priority'
+	// Compound index on 'completed' and 'priority'.
 	// Used for combined filters: GET /tasks?completed=false&priority=high
-	{
+	RegisterIndex(IndexSpec{
+		Name: "idx_completed_priority",
 		Keys: bson.D{
 			{Key: "completed", Value: 1},
 			{Key: "priority", Value: 1},
 		},
-		Options: options.Index().
-			SetName("idx_completed_priority"),
-	},
+	})
 
-	// Index on 'createdAt' for sorting (descending for newest first)
+	// Index on 'createdAt' for sorting (descending for newest first).
 	// Used in: Default sorting of tasks
-	{
+	RegisterIndex(IndexSpec{
+		Name: "idx_created_at",
 		Keys: bson.D{{Key: "createdAt", Value: -1}},
-		Options: options.Index().
-			SetName("idx_created_at"),
-	},
-
-	// Index on 'dueDate' for queries and sorting
-	// Useful for finding overdue tasks
-	{
-		Keys: bson.D{{Key: "dueDate", Value: 1}},
-		Options: options.Index().
-			SetName("idx_due_date").
-			SetSparse(true), // Only index documents that have dueDate
-	},
-
-	// Index on '_v' (version) for optimistic locking
-	// Useful for tracking document versions
-	{
+	})
+
+	// Index on 'dueDate' for queries and sorting, only while a task is
+	// still active - a task that's completed or canceled no longer needs
+	// to show up in an overdue-tasks query. The partialFilterExpression
+	// already excludes documents without a matching status, so this isn't
+	// also Sparse: Mongo rejects an index that's both sparse and partial.
+	RegisterIndex(IndexSpec{
+		Name:                    "idx_due_date",
+		Keys:                    bson.D{{Key: "dueDate", Value: 1}},
+		PartialFilterExpression: bson.M{"status": "active"},
+	})
+
+	// Index on '_v' (version) for optimistic locking.
+	RegisterIndex(IndexSpec{
+		Name: "idx_version",
 		Keys: bson.D{{Key: "_v", Value: 1}},
-		Options: options.Index().
-			SetName("idx_version"),
-	},
+	})
+
+	// TTL index that purges completed/canceled tasks 90 days after their
+	// last update, so the collection doesn't grow unbounded with
+	// long-finished work. It only applies to terminal tasks: an active or
+	// paused task is never reaped no matter how old updatedAt gets.
+	RegisterIndex(IndexSpec{
+		Name:                    "idx_terminal_ttl",
+		Keys:                    bson.D{{Key: "updatedAt", Value: 1}},
+		ExpireAfter:             90 * 24 * time.Hour,
+		PartialFilterExpression: bson.M{"status": bson.M{"$in": []string{"completed", "canceled"}}},
+	})
 }
 
 // IndexManager manages MongoDB indexes for the tasks collection
@@ -79,13 +127,21 @@ func NewIndexManager(collection *mongo.Collection) *IndexManager {
 	}
 }
 
+func indexModels() []mongo.IndexModel {
+	models := make([]mongo.IndexModel, len(registeredIndexes))
+	for i, spec := range registeredIndexes {
+		models[i] = spec.toIndexModel()
+	}
+	return models
+}
+
 // CreateIndexes creates all required indexes for the tasks collection
 // This should be called when the application starts
 func (im *IndexManager) CreateIndexes(ctx context.Context) error {
 	log.Println("Creating MongoDB indexes...")
 
 	// Create indexes
-	names, err := im.collection.Indexes().CreateMany(ctx, indexModels)
+	names, err := im.collection.Indexes().CreateMany(ctx, indexModels())
 	if err != nil {
 		log.Printf("Error creating indexes: %v", err)
 		return err
@@ -206,40 +262,64 @@ func (im *IndexManager) GetIndexStats(ctx context.Context) ([]bson.M, error) {
 func (im *IndexManager) EnsureIndexes(ctx context.Context) error {
 	log.Println("Ensuring MongoDB indexes exist...")
 
-	// Get existing indexes
-	existingIndexes, err := im.ListIndexes(ctx)
+	diff, err := im.DiffIndexes(ctx)
 	if err != nil {
 		return err
 	}
 
-	// Map existing index names
-	existingNames := make(map[string]bool)
-	for _, idx := range existingIndexes {
+	if len(diff.Missing) > 0 {
+		log.Printf("Missing indexes: %v. Creating them...", diff.Missing)
+		return im.CreateIndexes(ctx)
+	}
+
+	log.Println("All indexes exist")
+	return nil
+}
+
+// IndexDiff reports drift between the registered IndexSpecs and the indexes
+// actually present on the collection.
+type IndexDiff struct {
+	// Missing holds registered index names absent from the collection.
+	Missing []string
+	// Extra holds index names present on the collection (other than _id_)
+	// that aren't registered, e.g. left over from a removed IndexSpec.
+	Extra []string
+}
+
+// DiffIndexes compares registeredIndexes against the collection's live
+// indexes by name, without touching anything. Callers decide what to do
+// about drift - CreateIndexes for Missing, a manual DropOne for Extra -
+// rather than DiffIndexes silently reconciling on their behalf.
+func (im *IndexManager) DiffIndexes(ctx context.Context) (IndexDiff, error) {
+	existing, err := im.ListIndexes(ctx)
+	if err != nil {
+		return IndexDiff{}, err
+	}
+
+	existingNames := make(map[string]bool, len(existing))
+	for _, idx := range existing {
 		if name, ok := idx["name"].(string); ok {
 			existingNames[name] = true
 		}
 	}
 
-	// Extract required index names from our index models
-	requiredIndexes := make([]string, 0, len(indexModels))
-	for _, model := range indexModels {
-		if model.Options != nil && model.Options.Name != nil {
-			requiredIndexes = append(requiredIndexes, *model.Options.Name)
-		}
+	registeredNames := make(map[string]bool, len(registeredIndexes))
+	for _, spec := range registeredIndexes {
+		registeredNames[spec.Name] = true
 	}
 
-	missingIndexes := []string{}
-	for _, name := range requiredIndexes {
-		if !existingNames[name] {
-			missingIndexes = append(missingIndexes, name)
+	var diff IndexDiff
+	for _, spec := range registeredIndexes {
+		if !existingNames[spec.Name] {
+			diff.Missing = append(diff.Missing, spec.Name)
 		}
 	}
-
-	if len(missingIndexes) > 0 {
-		log.Printf("Missing indexes: %v. Creating them...", missingIndexes)
-		return im.CreateIndexes(ctx)
+	for name := range existingNames {
+		if name == "_id_" || registeredNames[name] {
+			continue
+		}
+		diff.Extra = append(diff.Extra, name)
 	}
 
-	log.Println("All indexes exist")
-	return nil
+	return diff, nil
 }
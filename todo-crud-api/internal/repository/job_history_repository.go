@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// JobHistoryRepository persists the scheduler's record of each task
+// execution, so operators can answer "did this task's last run succeed?"
+type JobHistoryRepository interface {
+	Record(ctx context.Context, entry *models.JobHistory) error
+	// FindByTaskID returns taskID's history, most recent first.
+	FindByTaskID(ctx context.Context, taskID primitive.ObjectID) ([]*models.JobHistory, error)
+	// Start inserts entry as JobStatusRunning, filling in its ID and
+	// StartedAt, for callers that want an in-progress record visible before
+	// the execution finishes (unlike Record, which is a single write after
+	// the fact).
+	Start(ctx context.Context, entry *models.JobHistory) error
+	// Finish marks a Start-ed entry as succeeded or failed, setting EndedAt
+	// and, on failure, Error.
+	Finish(ctx context.Context, id primitive.ObjectID, status models.JobStatus, cause error) error
+	// FindByScheduleID returns scheduleID's execution history, most recent first.
+	FindByScheduleID(ctx context.Context, scheduleID primitive.ObjectID) ([]*models.JobHistory, error)
+	// CountByScheduleID tallies scheduleID's execution history by status.
+	CountByScheduleID(ctx context.Context, scheduleID primitive.ObjectID) (models.ExecutionCounts, error)
+}
+
+type jobHistoryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewJobHistoryRepository creates a new Mongo-backed job history repository.
+func NewJobHistoryRepository(db *mongo.Database, collectionName string) JobHistoryRepository {
+	return &jobHistoryRepository{collection: db.Collection(collectionName)}
+}
+
+// Record inserts a completed or failed execution entry.
+func (r *jobHistoryRepository) Record(ctx context.Context, entry *models.JobHistory) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// FindByTaskID retrieves taskID's execution history, most recent first.
+func (r *jobHistoryRepository) FindByTaskID(ctx context.Context, taskID primitive.ObjectID) ([]*models.JobHistory, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"taskId": taskID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []*models.JobHistory{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// Start inserts entry as JobStatusRunning.
+func (r *jobHistoryRepository) Start(ctx context.Context, entry *models.JobHistory) error {
+	if entry.ID.IsZero() {
+		entry.ID = primitive.NewObjectID()
+	}
+	entry.Status = models.JobStatusRunning
+
+	_, err := r.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// Finish marks a Start-ed entry as succeeded or failed.
+func (r *jobHistoryRepository) Finish(ctx context.Context, id primitive.ObjectID, status models.JobStatus, cause error) error {
+	now := time.Now()
+	set := bson.M{
+		"status":  status,
+		"endedAt": now,
+	}
+	if cause != nil {
+		set["error"] = cause.Error()
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": set})
+	return err
+}
+
+// FindByScheduleID retrieves scheduleID's execution history, most recent first.
+func (r *jobHistoryRepository) FindByScheduleID(ctx context.Context, scheduleID primitive.ObjectID) ([]*models.JobHistory, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "startedAt", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"scheduleId": scheduleID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []*models.JobHistory{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// CountByScheduleID tallies scheduleID's execution history by status, the
+// same per-status CountDocuments pattern task_service.GetTaskStats uses.
+func (r *jobHistoryRepository) CountByScheduleID(ctx context.Context, scheduleID primitive.ObjectID) (models.ExecutionCounts, error) {
+	base := bson.M{"scheduleId": scheduleID}
+	var counts models.ExecutionCounts
+
+	total, err := r.collection.CountDocuments(ctx, base)
+	if err != nil {
+		return counts, err
+	}
+	counts.Total = total
+
+	succeeded, err := r.collection.CountDocuments(ctx, withStatus(base, models.JobStatusSucceeded))
+	if err != nil {
+		return counts, err
+	}
+	counts.Succeeded = succeeded
+
+	failed, err := r.collection.CountDocuments(ctx, withStatus(base, models.JobStatusFailed))
+	if err != nil {
+		return counts, err
+	}
+	counts.Failed = failed
+
+	inProgress, err := r.collection.CountDocuments(ctx, withStatus(base, models.JobStatusRunning))
+	if err != nil {
+		return counts, err
+	}
+	counts.InProgress = inProgress
+
+	return counts, nil
+}
+
+// withStatus returns a copy of base with status appended, so each
+// CountByScheduleID call filters independently without mutating base.
+func withStatus(base bson.M, status models.JobStatus) bson.M {
+	filter := bson.M{"status": status}
+	for k, v := range base {
+		filter[k] = v
+	}
+	return filter
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultJobLease is how long a worker holds a job before it is considered
+// abandoned and eligible for another worker to acquire.
+const DefaultJobLease = 30 * time.Second
+
+// JobRepository defines the persistence operations for the job/execution subsystem.
+type JobRepository interface {
+	Create(ctx context.Context, job *models.Job) error
+	// AcquireNext atomically claims the oldest pending (or lease-expired) job for workerID.
+	// It returns (nil, nil) when no job is currently available.
+	AcquireNext(ctx context.Context, workerID string, lease time.Duration) (*models.Job, error)
+	Complete(ctx context.Context, id primitive.ObjectID) error
+	Fail(ctx context.Context, id primitive.ObjectID, cause error) error
+	Heartbeat(ctx context.Context, id primitive.ObjectID, lease time.Duration) error
+}
+
+type jobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewJobRepository creates a new Mongo-backed job repository.
+func NewJobRepository(db *mongo.Database, collectionName string) JobRepository {
+	return &jobRepository{collection: db.Collection(collectionName)}
+}
+
+// Create inserts a new pending job.
+func (r *jobRepository) Create(ctx context.Context, job *models.Job) error {
+	if job.ID.IsZero() {
+		job.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	job.Status = models.JobStatusPending
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, job)
+	return err
+}
+
+// AcquireNext claims a pending job, or a running job whose lease has expired
+// (meaning its worker crashed without completing or failing it).
+func (r *jobRepository) AcquireNext(ctx context.Context, workerID string, lease time.Duration) (*models.Job, error) {
+	now := time.Now()
+	expiresAt := now.Add(lease)
+
+	filter := bson.M{
+		"$or": []bson.M{
+			{"status": models.JobStatusPending},
+			{
+				"status":         models.JobStatusRunning,
+				"leaseExpiresAt": bson.M{"$lt": now},
+			},
+		},
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":         models.JobStatusRunning,
+			"workerId":       workerID,
+			"leaseExpiresAt": expiresAt,
+			"updatedAt":      now,
+		},
+	}
+
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var job models.Job
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&job)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	job.LastHeartbeatAt = &now
+	return &job, nil
+}
+
+// Complete marks a job as succeeded.
+func (r *jobRepository) Complete(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.JobStatusSucceeded,
+			"updatedAt": time.Now(),
+		},
+	}
+	return r.updateOne(ctx, id, update)
+}
+
+// Fail marks a job as failed and records the error, bumping its retry counter.
+func (r *jobRepository) Fail(ctx context.Context, id primitive.ObjectID, cause error) error {
+	message := ""
+	if cause != nil {
+		message = cause.Error()
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.JobStatusFailed,
+			"error":     message,
+			"updatedAt": time.Now(),
+		},
+		"$inc": bson.M{"retries": 1},
+	}
+	return r.updateOne(ctx, id, update)
+}
+
+// Heartbeat extends a running job's lease so other workers don't reclaim it.
+func (r *jobRepository) Heartbeat(ctx context.Context, id primitive.ObjectID, lease time.Duration) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"leaseExpiresAt":  now.Add(lease),
+			"lastHeartbeatAt": now,
+			"updatedAt":       now,
+		},
+	}
+	return r.updateOne(ctx, id, update)
+}
+
+func (r *jobRepository) updateOne(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return models.ErrJobNotFound
+	}
+	return nil
+}
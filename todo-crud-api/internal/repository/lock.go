@@ -0,0 +1,108 @@
+// Package repository provides data access layer for MongoDB operations.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrLockNotAcquired is returned by TryLock when another holder already owns
+// the lock.
+var ErrLockNotAcquired = errors.New("lock not acquired")
+
+// DefaultLockTTL bounds how long a lock document survives if its holder
+// crashes before releasing it; the TTL index reaps it automatically.
+const DefaultLockTTL = 30 * time.Second
+
+// lockRetryInterval is how long WithLock waits between acquisition attempts.
+const lockRetryInterval = 50 * time.Millisecond
+
+// lockDoc is the document stored per held lock. The unique index on name
+// is what makes acquisition atomic: only one InsertOne for a given name can
+// succeed at a time.
+type lockDoc struct {
+	Name       string    `bson:"name"`
+	AcquiredAt time.Time `bson:"acquired_at"`
+}
+
+// Locker implements a Mongo-backed advisory lock, in the spirit of Coder's
+// AcquireLock/TryAcquireLock: callers serialize a critical section across
+// replicas by racing to insert a uniquely-keyed document.
+type Locker struct {
+	collection *mongo.Collection
+}
+
+// NewLocker creates a Locker backed by the given collection. Call
+// EnsureIndexes once at startup so the unique/TTL indexes exist.
+func NewLocker(collection *mongo.Collection) *Locker {
+	return &Locker{collection: collection}
+}
+
+// EnsureIndexes creates the unique index on name and the TTL index on
+// acquired_at. It is idempotent and safe to call multiple times.
+func (l *Locker) EnsureIndexes(ctx context.Context) error {
+	_, err := l.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().SetName("idx_lock_name").SetUnique(true),
+		},
+		{
+			Keys: bson.D{{Key: "acquired_at", Value: 1}},
+			Options: options.Index().
+				SetName("idx_lock_ttl").
+				SetExpireAfterSeconds(int32(DefaultLockTTL.Seconds())),
+		},
+	})
+	return err
+}
+
+// TryLock attempts to acquire the named lock without blocking. It returns
+// ErrLockNotAcquired if another holder currently owns it.
+func (l *Locker) TryLock(ctx context.Context, name string) error {
+	_, err := l.collection.InsertOne(ctx, lockDoc{Name: name, AcquiredAt: time.Now()})
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return ErrLockNotAcquired
+		}
+		return err
+	}
+	return nil
+}
+
+// Unlock releases a lock previously acquired with TryLock.
+func (l *Locker) Unlock(ctx context.Context, name string) error {
+	_, err := l.collection.DeleteOne(ctx, bson.M{"name": name})
+	return err
+}
+
+// WithLock acquires the named lock, runs fn, and releases the lock
+// afterwards, retrying acquisition until ctx is done. Use it to serialize a
+// read-modify-write across replicas for a single logical resource.
+func (l *Locker) WithLock(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	for {
+		err := l.TryLock(ctx, name)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(lockRetryInterval):
+		}
+	}
+
+	defer func() {
+		_ = l.Unlock(context.WithoutCancel(ctx), name)
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryTaskRepository is an in-memory TaskRepository. It's meant for local
+// development and tests that want real optimistic-concurrency semantics
+// without standing up a database; data does not survive process restarts.
+type memoryTaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[primitive.ObjectID]*models.Task
+}
+
+// NewMemoryTaskRepository creates a new in-memory repository instance.
+func NewMemoryTaskRepository() TaskRepository {
+	return &memoryTaskRepository{
+		tasks: make(map[primitive.ObjectID]*models.Task),
+	}
+}
+
+// Create inserts a new task into the map
+func (r *memoryTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	stored := *task
+	r.tasks[task.ID] = &stored
+	return nil
+}
+
+// FindAll retrieves tasks matching filter, ordered by filter.Sort (most
+// recent first by default), mirroring the Mongo repository's
+// cursor-pagination semantics: the cursor excludes documents via a keyset
+// OR of sortField/_id, not a plain AND, so rows sharing the cursor's
+// sortField value with a later/earlier _id aren't dropped. Fields is
+// ignored: only the Mongo backend projects.
+func (r *memoryTaskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	limit := 0
+	var rawSort string
+	if filter != nil {
+		limit = filter.Limit
+		rawSort = filter.Sort
+	}
+	sortField, desc, ok := models.ParseSort(rawSort)
+	if !ok {
+		return nil, "", fmt.Errorf("invalid sort field %q", rawSort)
+	}
+
+	var cur *Cursor
+	if filter != nil && filter.Cursor != "" {
+		decoded, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := checkCursorSort(decoded, rawSort); err != nil {
+			return nil, "", err
+		}
+		cur = &decoded
+	}
+
+	tasks := make([]*models.Task, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		if !matchesFilter(task, filter) {
+			continue
+		}
+		if cur != nil {
+			v := cursorValue(task, sortField)
+			idCmp := bytes.Compare(task.ID[:], cur.ID[:])
+			if desc && !(v < cur.Value || (v == cur.Value && idCmp < 0)) {
+				continue
+			}
+			if !desc && !(v > cur.Value || (v == cur.Value && idCmp > 0)) {
+				continue
+			}
+		}
+		copied := *task
+		copied.EnsureStatus()
+		tasks = append(tasks, &copied)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		vi, vj := cursorValue(tasks[i], sortField), cursorValue(tasks[j], sortField)
+		if vi != vj {
+			if desc {
+				return vi > vj
+			}
+			return vi < vj
+		}
+		idCmp := bytes.Compare(tasks[i].ID[:], tasks[j].ID[:])
+		if desc {
+			return idCmp > 0
+		}
+		return idCmp < 0
+	})
+
+	nextCursor := ""
+	if limit > 0 && len(tasks) > limit {
+		last := tasks[limit-1]
+		nextCursor = EncodeCursor(rawSort, cursorValue(last, sortField), last.ID)
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nextCursor, nil
+}
+
+// FindByID retrieves a task by ID
+func (r *memoryTaskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	task, ok := r.tasks[id]
+	if !ok {
+		return nil, errors.New(models.ErrTaskNotFound)
+	}
+
+	copied := *task
+	copied.EnsureStatus()
+	return &copied, nil
+}
+
+// Update updates an existing task, enforcing optimistic concurrency the same
+// way the Mongo repository does: the write only applies if the stored task
+// still has expectedVersion.
+func (r *memoryTaskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.tasks[id]
+	if !ok {
+		return errors.New(models.ErrTaskNotFound)
+	}
+	if existing.Version != expectedVersion {
+		return models.ErrVersionConflict
+	}
+
+	task.UpdatedAt = time.Now()
+	task.Version = expectedVersion + 1
+	stored := *task
+	r.tasks[id] = &stored
+	return nil
+}
+
+// Delete removes a task from the map
+func (r *memoryTaskRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tasks[id]; !ok {
+		return errors.New(models.ErrTaskNotFound)
+	}
+	delete(r.tasks, id)
+	return nil
+}
+
+// Count returns the number of tasks that match the filter
+func (r *memoryTaskRepository) Count(ctx context.Context, filter *models.TaskFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, task := range r.tasks {
+		if matchesFilter(task, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// BulkCreate inserts tasks one at a time, collecting a BulkResult per task
+// rather than stopping at the first error, mirroring the all-or-nothing-
+// per-item semantics of the Mongo backend's unordered BulkWrite.
+func (r *memoryTaskRepository) BulkCreate(ctx context.Context, tasks []*models.Task) ([]BulkResult, error) {
+	results := make([]BulkResult, len(tasks))
+	for i, task := range tasks {
+		err := r.Create(ctx, task)
+		results[i] = BulkResult{ID: task.ID.Hex(), Err: err}
+	}
+	return results, nil
+}
+
+// BulkDelete removes the tasks named by ids one at a time, collecting a
+// BulkResult per id rather than stopping at the first error.
+func (r *memoryTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ids))
+	for i, id := range ids {
+		err := r.Delete(ctx, id)
+		results[i] = BulkResult{ID: id.Hex(), Err: err}
+	}
+	return results, nil
+}
+
+// matchesFilter reports whether task satisfies filter, mirroring the BSON
+// filter built by the Mongo repository's FindAll/Count.
+func matchesFilter(task *models.Task, filter *models.TaskFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.Completed != nil && task.Completed != *filter.Completed {
+		return false
+	}
+	if filter.Priority != "" && task.Priority != filter.Priority {
+		return false
+	}
+	if filter.Status != "" && task.Status != filter.Status {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "testing"
+
+func TestMemoryTaskRepositoryConformance(t *testing.T) {
+	taskRepositoryConformance(t, func() TaskRepository {
+		return NewMemoryTaskRepository()
+	})
+}
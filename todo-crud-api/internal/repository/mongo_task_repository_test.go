@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestMongoTaskRepositoryConformance runs the shared conformance suite
+// against a real MongoDB instance. It's skipped unless TODO_MONGO_TEST_URI
+// is set, since it needs a live cluster.
+func TestMongoTaskRepositoryConformance(t *testing.T) {
+	uri := os.Getenv("TODO_MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("TODO_MONGO_TEST_URI not set; skipping MongoDB conformance test")
+	}
+
+	ctx := context.Background()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("mongo.Connect() error = %v", err)
+	}
+	defer client.Disconnect(ctx)
+
+	db := client.Database("todo_conformance_test")
+	collection := db.Collection("tasks")
+
+	taskRepositoryConformance(t, func() TaskRepository {
+		if err := collection.Drop(ctx); err != nil {
+			t.Fatalf("dropping tasks collection: %v", err)
+		}
+		return NewTaskRepository(db, "tasks")
+	})
+}
@@ -0,0 +1,348 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// postgresTaskRepository implements TaskRepository against a Postgres
+// "tasks" table, mirroring the Mongo document shape column-for-column so
+// the service and handler layers stay storage-agnostic.
+type postgresTaskRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresTaskRepository opens a connection pool to dsn and ensures the
+// tasks table exists.
+func NewPostgresTaskRepository(dsn string) (TaskRepository, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	repo := &postgresTaskRepository{db: db}
+	if err := repo.ensureSchema(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+func (r *postgresTaskRepository) ensureSchema(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS tasks (
+			id           TEXT PRIMARY KEY,
+			name         TEXT NOT NULL,
+			description  TEXT NOT NULL DEFAULT '',
+			completed    BOOLEAN NOT NULL DEFAULT FALSE,
+			status       TEXT NOT NULL DEFAULT '',
+			paused_at    TIMESTAMPTZ,
+			pause_reason TEXT NOT NULL DEFAULT '',
+			priority     TEXT NOT NULL DEFAULT '',
+			due_date     TIMESTAMPTZ,
+			created_at   TIMESTAMPTZ NOT NULL,
+			updated_at   TIMESTAMPTZ NOT NULL,
+			version      INTEGER NOT NULL DEFAULT 1,
+			doer_id      TEXT NOT NULL DEFAULT '',
+			owner_id     TEXT NOT NULL DEFAULT '',
+			trace_id     TEXT NOT NULL DEFAULT ''
+		)
+	`)
+	return err
+}
+
+// Create inserts a new task row
+func (r *postgresTaskRepository) Create(ctx context.Context, task *models.Task) error {
+	if task.ID.IsZero() {
+		task.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO tasks (id, name, description, completed, status, paused_at, pause_reason,
+			priority, due_date, created_at, updated_at, version, doer_id, owner_id, trace_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		task.ID.Hex(), task.Name, task.Description, task.Completed, string(task.Status), task.PausedAt, task.PauseReason,
+		task.Priority, task.DueDate, task.CreatedAt, task.UpdatedAt, task.Version, task.DoerID, task.OwnerID, task.TraceID,
+	)
+	return err
+}
+
+// FindAll retrieves tasks matching filter, ordered by filter.Sort (most
+// recent first by default). If
+// filter.Cursor is set, it resumes after that position; if filter.Limit is
+// set, it fetches one extra row to detect and report a next cursor. Fields
+// is ignored: only the Mongo backend projects.
+func (r *postgresTaskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, string, error) {
+	query, args := filteredTaskQuery(selectTaskColumns+" FROM tasks WHERE 1=1", filter)
+
+	limit := 0
+	var rawSort string
+	if filter != nil {
+		limit = filter.Limit
+		rawSort = filter.Sort
+	}
+	sortField, desc, ok := models.ParseSort(rawSort)
+	if !ok {
+		return nil, "", fmt.Errorf("invalid sort field %q", rawSort)
+	}
+	column := sortColumn(sortField)
+
+	cmp := "<"
+	if !desc {
+		cmp = ">"
+	}
+	if filter != nil && filter.Cursor != "" {
+		cur, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := checkCursorSort(cur, rawSort); err != nil {
+			return nil, "", err
+		}
+		args = append(args, sortCursorArg(sortField, cur.Value))
+		valueArg := len(args)
+		args = append(args, cur.ID.Hex())
+		idArg := len(args)
+		query += fmt.Sprintf(" AND (%s %s $%d OR (%s = $%d AND id %s $%d))",
+			column, cmp, valueArg, column, valueArg, cmp, idArg)
+	}
+
+	dir := "DESC"
+	if !desc {
+		dir = "ASC"
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s", column, dir, dir)
+
+	if limit > 0 {
+		args = append(args, limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		task.EnsureStatus()
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", err
+	}
+
+	nextCursor := ""
+	if limit > 0 && len(tasks) > limit {
+		last := tasks[limit-1]
+		nextCursor = EncodeCursor(rawSort, cursorValue(last, sortField), last.ID)
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nextCursor, nil
+}
+
+// FindByID retrieves a task by ID
+func (r *postgresTaskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+	row := r.db.QueryRowContext(ctx, selectTaskColumns+" FROM tasks WHERE id = $1", id.Hex())
+
+	task, err := scanTask(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New(models.ErrTaskNotFound)
+		}
+		return nil, err
+	}
+
+	task.EnsureStatus()
+	return task, nil
+}
+
+// Update updates an existing task, enforcing optimistic concurrency the same
+// way the Mongo repository does: the write only applies if the stored row
+// still has expectedVersion.
+func (r *postgresTaskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error {
+	task.UpdatedAt = time.Now()
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE tasks SET name = $1, description = $2, completed = $3, status = $4, paused_at = $5,
+			pause_reason = $6, priority = $7, due_date = $8, updated_at = $9, version = version + 1,
+			doer_id = $10, trace_id = $11
+		WHERE id = $12 AND version = $13`,
+		task.Name, task.Description, task.Completed, string(task.Status), task.PausedAt, task.PauseReason,
+		task.Priority, task.DueDate, task.UpdatedAt, task.DoerID, task.TraceID, id.Hex(), expectedVersion,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1)", id.Hex()).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return errors.New(models.ErrTaskNotFound)
+		}
+		return models.ErrVersionConflict
+	}
+
+	return nil
+}
+
+// Delete removes a task row
+func (r *postgresTaskRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM tasks WHERE id = $1", id.Hex())
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New(models.ErrTaskNotFound)
+	}
+
+	return nil
+}
+
+// BulkCreate inserts tasks one at a time, collecting a BulkResult per task
+// rather than stopping at the first error, mirroring the all-or-nothing-
+// per-item semantics of the Mongo backend's unordered BulkWrite.
+func (r *postgresTaskRepository) BulkCreate(ctx context.Context, tasks []*models.Task) ([]BulkResult, error) {
+	results := make([]BulkResult, len(tasks))
+	for i, task := range tasks {
+		err := r.Create(ctx, task)
+		results[i] = BulkResult{ID: task.ID.Hex(), Err: err}
+	}
+	return results, nil
+}
+
+// BulkDelete removes the tasks named by ids one at a time, collecting a
+// BulkResult per id rather than stopping at the first error.
+func (r *postgresTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]BulkResult, error) {
+	results := make([]BulkResult, len(ids))
+	for i, id := range ids {
+		err := r.Delete(ctx, id)
+		results[i] = BulkResult{ID: id.Hex(), Err: err}
+	}
+	return results, nil
+}
+
+// Count returns the number of tasks that match the filter
+func (r *postgresTaskRepository) Count(ctx context.Context, filter *models.TaskFilter) (int64, error) {
+	query, args := filteredTaskQuery("SELECT COUNT(*) FROM tasks WHERE 1=1", filter)
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// filteredTaskQuery appends the WHERE clauses matching filter onto base,
+// returning the completed query and its positional args.
+func filteredTaskQuery(base string, filter *models.TaskFilter) (string, []interface{}) {
+	query := base
+	var args []interface{}
+	if filter == nil {
+		return query, args
+	}
+
+	if filter.Completed != nil {
+		args = append(args, *filter.Completed)
+		query += fmt.Sprintf(" AND completed = $%d", len(args))
+	}
+	if filter.Priority != "" {
+		args = append(args, filter.Priority)
+		query += fmt.Sprintf(" AND priority = $%d", len(args))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		query += fmt.Sprintf(" AND status = $%d", len(args))
+	}
+
+	return query, args
+}
+
+// sortColumn returns the SQL expression FindAll orders and ranges by for
+// field, which has already passed models.ParseSort's allow-list. priority
+// is ranked by a CASE expression rather than its raw text column, matching
+// models.PriorityRank.
+func sortColumn(field string) string {
+	switch field {
+	case "dueDate":
+		return "due_date"
+	case "priority":
+		return "(CASE priority WHEN 'high' THEN 2 WHEN 'low' THEN 0 ELSE 1 END)"
+	default:
+		return "created_at"
+	}
+}
+
+// sortCursorArg converts a cursor's comparable int64 value back into the
+// type sortColumn's expression for field expects: a timestamp for the time
+// fields, the rank itself for priority.
+func sortCursorArg(field string, value int64) interface{} {
+	if field == "priority" {
+		return value
+	}
+	return time.Unix(0, value)
+}
+
+const selectTaskColumns = `SELECT id, name, description, completed, status, paused_at, pause_reason,
+	priority, due_date, created_at, updated_at, version, doer_id, owner_id, trace_id`
+
+// rowScanner abstracts *sql.Row and *sql.Rows so scanTask works for both
+// FindByID's single-row and FindAll's multi-row paths.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTask(s rowScanner) (*models.Task, error) {
+	var task models.Task
+	var idHex string
+	var status string
+
+	if err := s.Scan(&idHex, &task.Name, &task.Description, &task.Completed, &status, &task.PausedAt, &task.PauseReason,
+		&task.Priority, &task.DueDate, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.DoerID, &task.OwnerID, &task.TraceID); err != nil {
+		return nil, err
+	}
+
+	id, err := primitive.ObjectIDFromHex(idHex)
+	if err != nil {
+		return nil, err
+	}
+	task.ID = id
+	task.Status = models.Status(status)
+
+	return &task, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"os"
+	"testing"
+)
+
+// TestPostgresTaskRepositoryConformance runs the shared conformance suite
+// against a real Postgres instance. It's skipped unless
+// TODO_POSTGRES_TEST_DSN is set, since it needs a live database.
+func TestPostgresTaskRepositoryConformance(t *testing.T) {
+	dsn := os.Getenv("TODO_POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("TODO_POSTGRES_TEST_DSN not set; skipping Postgres conformance test")
+	}
+
+	repo, err := NewPostgresTaskRepository(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgresTaskRepository() error = %v", err)
+	}
+	pgRepo := repo.(*postgresTaskRepository)
+
+	taskRepositoryConformance(t, func() TaskRepository {
+		if _, err := pgRepo.db.Exec("TRUNCATE TABLE tasks"); err != nil {
+			t.Fatalf("truncating tasks table: %v", err)
+		}
+		return pgRepo
+	})
+}
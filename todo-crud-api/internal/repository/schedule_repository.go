@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"todo-list-golang/internal/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrScheduleNotFound is returned when a schedule lookup targets a document
+// that does not exist in the collection.
+var ErrScheduleNotFound = errors.New("schedule not found")
+
+// ScheduleRepository persists Schedules and leases the ones due to run, the
+// same findOneAndUpdate lease pattern scheduler.Worker uses against the
+// tasks collection, so multiple app instances can run the ScheduleWorker
+// concurrently without double-creating a Task for the same occurrence.
+type ScheduleRepository interface {
+	Create(ctx context.Context, schedule *models.Schedule) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Schedule, error)
+	// LeaseNextDue atomically claims the oldest due, unleased (or
+	// lease-expired) schedule, setting LockedUntil to now+lease. It returns
+	// (nil, nil) when none is currently due.
+	LeaseNextDue(ctx context.Context, lease time.Duration) (*models.Schedule, error)
+	// Reschedule advances id's NextRunAt to next and clears its lease, the
+	// same way scheduler.Worker.reschedule updates a Task.
+	Reschedule(ctx context.Context, id primitive.ObjectID, next time.Time) error
+}
+
+type scheduleRepository struct {
+	collection *mongo.Collection
+}
+
+// NewScheduleRepository creates a new Mongo-backed schedule repository.
+func NewScheduleRepository(db *mongo.Database, collectionName string) ScheduleRepository {
+	return &scheduleRepository{collection: db.Collection(collectionName)}
+}
+
+// Create inserts a new schedule.
+func (r *scheduleRepository) Create(ctx context.Context, schedule *models.Schedule) error {
+	if schedule.ID.IsZero() {
+		schedule.ID = primitive.NewObjectID()
+	}
+
+	now := time.Now()
+	schedule.CreatedAt = now
+	schedule.UpdatedAt = now
+
+	_, err := r.collection.InsertOne(ctx, schedule)
+	return err
+}
+
+// FindByID returns the schedule with the given id.
+func (r *scheduleRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Schedule, error) {
+	var schedule models.Schedule
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&schedule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrScheduleNotFound
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// LeaseNextDue claims the oldest due schedule whose lease is either unset or
+// itself expired (meaning the worker that held it crashed before
+// rescheduling it).
+func (r *scheduleRepository) LeaseNextDue(ctx context.Context, lease time.Duration) (*models.Schedule, error) {
+	now := time.Now()
+
+	filter := bson.M{
+		"nextRunAt": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"lockedUntil": bson.M{"$exists": false}},
+			{"lockedUntil": nil},
+			{"lockedUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"lockedUntil": now.Add(lease)},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextRunAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var schedule models.Schedule
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&schedule)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &schedule, nil
+}
+
+// Reschedule advances id's NextRunAt to next and clears its lease.
+func (r *scheduleRepository) Reschedule(ctx context.Context, id primitive.ObjectID, next time.Time) error {
+	update := bson.M{
+		"$set":   bson.M{"nextRunAt": next, "updatedAt": time.Now()},
+		"$unset": bson.M{"lockedUntil": ""},
+	}
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	return err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -17,11 +18,32 @@ import (
 // TaskRepository defines the interface for database operations
 type TaskRepository interface {
 	Create(ctx context.Context, task *models.Task) error
-	FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error)
+	// FindAll returns tasks matching filter, ordered by filter.Sort (most
+	// recent first by default; see models.AllowedSortFields). When
+	// filter.Limit is set, it returns at most that many tasks plus an
+	// opaque nextCursor for the following page (empty if this was the
+	// last page).
+	FindAll(ctx context.Context, filter *models.TaskFilter) (tasks []*models.Task, nextCursor string, err error)
 	FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error)
-	Update(ctx context.Context, id primitive.ObjectID, task *models.Task) error
+	Update(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	Count(ctx context.Context, filter *models.TaskFilter) (int64, error)
+	// BulkCreate inserts tasks and reports one BulkResult per task, in the
+	// same order, so a partial failure doesn't keep the rest from being
+	// created.
+	BulkCreate(ctx context.Context, tasks []*models.Task) ([]BulkResult, error)
+	// BulkDelete removes the tasks named by ids and reports one BulkResult
+	// per id, in the same order; an id with no matching task fails with
+	// models.ErrTaskNotFound rather than being silently skipped.
+	BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]BulkResult, error)
+}
+
+// BulkResult is one item's outcome from a BulkCreate/BulkDelete call: ID is
+// that item's task id (populated even on failure, so the caller can
+// correlate it back to what it sent), and Err is nil on success.
+type BulkResult struct {
+	ID  string
+	Err error
 }
 
 // taskRepository implements TaskRepository
@@ -56,10 +78,16 @@ func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
 	return nil
 }
 
-// FindAll retrieves all tasks with optional filters
-func (r *taskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error) {
-	// Build the BSON filter
+// FindAll retrieves tasks matching filter, ordered by filter.Sort (most
+// recent first by default). If filter.Cursor is set, it resumes after that
+// position; if filter.Limit is set, it fetches one extra document to
+// detect and report a next cursor. Sorting by "priority" goes through
+// findAllByPriorityRank, since Mongo can't range/sort a string field by
+// models.PriorityRank without a computed expression.
+func (r *taskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, string, error) {
 	bsonFilter := bson.M{}
+	limit := 0
+	var rawSort string
 	if filter != nil {
 		if filter.Completed != nil {
 			bsonFilter["completed"] = *filter.Completed
@@ -67,14 +95,59 @@ func (r *taskRepository) FindAll(ctx context.Context, filter *models.TaskFilter)
 		if filter.Priority != "" {
 			bsonFilter["priority"] = filter.Priority
 		}
+		if filter.Status != "" {
+			bsonFilter["status"] = filter.Status
+		}
+		limit = filter.Limit
+		rawSort = filter.Sort
+	}
+
+	sortField, desc, ok := models.ParseSort(rawSort)
+	if !ok {
+		return nil, "", fmt.Errorf("invalid sort field %q", rawSort)
+	}
+
+	if sortField == "priority" {
+		return r.findAllByPriorityRank(ctx, bsonFilter, filter, desc, limit)
 	}
 
-	// Sort options (most recent first)
-	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cmp := "$lt"
+	if !desc {
+		cmp = "$gt"
+	}
+	if filter != nil && filter.Cursor != "" {
+		cur, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := checkCursorSort(cur, rawSort); err != nil {
+			return nil, "", err
+		}
+		bsonFilter["$or"] = bson.A{
+			bson.M{sortField: bson.M{cmp: time.Unix(0, cur.Value)}},
+			bson.M{sortField: time.Unix(0, cur.Value), "_id": bson.M{cmp: cur.ID}},
+		}
+	}
+
+	sortDir := -1
+	if !desc {
+		sortDir = 1
+	}
+	opts := options.Find().SetSort(bson.D{{Key: sortField, Value: sortDir}, {Key: "_id", Value: sortDir}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit) + 1)
+	}
+	if filter != nil && len(filter.Fields) > 0 {
+		projection := bson.M{}
+		for _, field := range filter.Fields {
+			projection[field] = 1
+		}
+		opts.SetProjection(projection)
+	}
 
 	cursor, err := r.collection.Find(ctx, bsonFilter, opts)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer func() {
 		if err := cursor.Close(ctx); err != nil {
@@ -84,7 +157,7 @@ func (r *taskRepository) FindAll(ctx context.Context, filter *models.TaskFilter)
 
 	var tasks []*models.Task
 	if err := cursor.All(ctx, &tasks); err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Return empty slice instead of nil if there are no tasks
@@ -92,7 +165,103 @@ func (r *taskRepository) FindAll(ctx context.Context, filter *models.TaskFilter)
 		tasks = []*models.Task{}
 	}
 
-	return tasks, nil
+	for _, task := range tasks {
+		task.EnsureStatus()
+	}
+
+	nextCursor := ""
+	if limit > 0 && len(tasks) > limit {
+		last := tasks[limit-1]
+		nextCursor = EncodeCursor(rawSort, cursorValue(last, sortField), last.ID)
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nextCursor, nil
+}
+
+// priorityRankExpr is the $switch expression findAllByPriorityRank adds as
+// "_priorityRank" so priority can be ranged and sorted like any other
+// field, matching models.PriorityRank.
+var priorityRankExpr = bson.M{
+	"$switch": bson.M{
+		"branches": bson.A{
+			bson.M{"case": bson.M{"$eq": bson.A{"$priority", "high"}}, "then": 2},
+			bson.M{"case": bson.M{"$eq": bson.A{"$priority", "low"}}, "then": 0},
+		},
+		"default": 1,
+	},
+}
+
+// findAllByPriorityRank is FindAll's "sort=priority" path: it computes
+// _priorityRank via an aggregation pipeline so the range/sort happens
+// server-side instead of sorting the string field alphabetically.
+func (r *taskRepository) findAllByPriorityRank(ctx context.Context, bsonFilter bson.M, filter *models.TaskFilter, desc bool, limit int) ([]*models.Task, string, error) {
+	cmp := "$lt"
+	if !desc {
+		cmp = "$gt"
+	}
+	if filter != nil && filter.Cursor != "" {
+		cur, err := DecodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if err := checkCursorSort(cur, filter.Sort); err != nil {
+			return nil, "", err
+		}
+		bsonFilter["$or"] = bson.A{
+			bson.M{"_priorityRank": bson.M{cmp: cur.Value}},
+			bson.M{"_priorityRank": cur.Value, "_id": bson.M{cmp: cur.ID}},
+		}
+	}
+
+	sortDir := -1
+	if !desc {
+		sortDir = 1
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$addFields", Value: bson.M{"_priorityRank": priorityRankExpr}}},
+		{{Key: "$match", Value: bsonFilter}},
+		{{Key: "$sort", Value: bson.D{{Key: "_priorityRank", Value: sortDir}, {Key: "_id", Value: sortDir}}}},
+	}
+	if limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: int64(limit) + 1}})
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}()
+
+	var tasks []*models.Task
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return nil, "", err
+	}
+	if tasks == nil {
+		tasks = []*models.Task{}
+	}
+
+	for _, task := range tasks {
+		task.EnsureStatus()
+	}
+
+	nextCursor := ""
+	if limit > 0 && len(tasks) > limit {
+		last := tasks[limit-1]
+		sort := "priority"
+		if desc {
+			sort = "-priority"
+		}
+		nextCursor = EncodeCursor(sort, cursorValue(last, "priority"), last.ID)
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nextCursor, nil
 }
 
 // FindByID retrieves a task by ID
@@ -106,30 +275,39 @@ func (r *taskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 		return nil, err
 	}
 
+	task.EnsureStatus()
 	return &task, nil
 }
 
-// Update updates an existing task
-func (r *taskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task) error {
+// Update updates an existing task, enforcing optimistic concurrency: the
+// write only applies if the stored document still has expectedVersion. If
+// MatchedCount is 0, the caller learns which by re-reading: a conflict means
+// the document exists at a different version, while a deleted document
+// returns ErrTaskNotFound.
+func (r *taskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error {
 	// Update the timestamp
 	task.UpdatedAt = time.Now()
 
-	// Remove the ID from the update document
 	update := bson.M{
 		"$set": bson.M{
 			"name":        task.Name,
 			"description": task.Description,
 			"completed":   task.Completed,
+			"status":      task.Status,
+			"pausedAt":    task.PausedAt,
+			"pauseReason": task.PauseReason,
 			"priority":    task.Priority,
 			"dueDate":     task.DueDate,
 			"updatedAt":   task.UpdatedAt,
-			"_v":          task.Version, // Update document version
+			"doerId":      task.DoerID,
+			"traceId":     task.TraceID,
 		},
+		"$inc": bson.M{"_v": 1},
 	}
 
 	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": id},
+		bson.M{"_id": id, "_v": expectedVersion},
 		update,
 	)
 	if err != nil {
@@ -137,7 +315,14 @@ func (r *taskRepository) Update(ctx context.Context, id primitive.ObjectID, task
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New(models.ErrTaskNotFound)
+		count, countErr := r.collection.CountDocuments(ctx, bson.M{"_id": id})
+		if countErr != nil {
+			return countErr
+		}
+		if count == 0 {
+			return errors.New(models.ErrTaskNotFound)
+		}
+		return models.ErrVersionConflict
 	}
 
 	return nil
@@ -168,6 +353,9 @@ func (r *taskRepository) Count(ctx context.Context, filter *models.TaskFilter) (
 		if filter.Priority != "" {
 			bsonFilter["priority"] = filter.Priority
 		}
+		if filter.Status != "" {
+			bsonFilter["status"] = filter.Status
+		}
 	}
 
 	count, err := r.collection.CountDocuments(ctx, bsonFilter)
@@ -177,3 +365,100 @@ func (r *taskRepository) Count(ctx context.Context, filter *models.TaskFilter) (
 
 	return count, nil
 }
+
+// BulkCreate inserts tasks via a single unordered BulkWrite, so one invalid
+// document fails only its own BulkResult instead of aborting the rest.
+func (r *taskRepository) BulkCreate(ctx context.Context, tasks []*models.Task) ([]BulkResult, error) {
+	now := time.Now()
+	writeModels := make([]mongo.WriteModel, len(tasks))
+	results := make([]BulkResult, len(tasks))
+	for i, task := range tasks {
+		if task.ID.IsZero() {
+			task.ID = primitive.NewObjectID()
+		}
+		task.CreatedAt = now
+		task.UpdatedAt = now
+		writeModels[i] = mongo.NewInsertOneModel().SetDocument(task)
+		results[i] = BulkResult{ID: task.ID.Hex()}
+	}
+
+	_, err := r.collection.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		var bwErr mongo.BulkWriteException
+		if !errors.As(err, &bwErr) {
+			return nil, err
+		}
+		for _, we := range bwErr.WriteErrors {
+			results[we.Index].Err = we.Err
+		}
+	}
+
+	return results, nil
+}
+
+// existingIDs reports which of ids have a matching document, so
+// BulkDelete can tell "nothing to delete" apart from "delete failed": a
+// BulkWrite DeleteOneModel that matches nothing succeeds silently, with no
+// per-item signal the caller could use to tell the two apart.
+func (r *taskRepository) existingIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]bool, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": ids}}, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cursor.Close(ctx); err != nil {
+			log.Printf("Error closing cursor: %v", err)
+		}
+	}()
+
+	found := make(map[primitive.ObjectID]bool, len(ids))
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		found[doc.ID] = true
+	}
+	return found, cursor.Err()
+}
+
+// BulkDelete removes the tasks named by ids via a single unordered
+// BulkWrite, so one failure doesn't abort the rest.
+func (r *taskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]BulkResult, error) {
+	found, err := r.existingIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(ids))
+	writeModels := make([]mongo.WriteModel, 0, len(ids))
+	modelIndex := make([]int, 0, len(ids))
+	for i, id := range ids {
+		results[i] = BulkResult{ID: id.Hex()}
+		if !found[id] {
+			results[i].Err = errors.New(models.ErrTaskNotFound)
+			continue
+		}
+		writeModels = append(writeModels, mongo.NewDeleteOneModel().SetFilter(bson.M{"_id": id}))
+		modelIndex = append(modelIndex, i)
+	}
+
+	if len(writeModels) == 0 {
+		return results, nil
+	}
+
+	_, err = r.collection.BulkWrite(ctx, writeModels, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		var bwErr mongo.BulkWriteException
+		if !errors.As(err, &bwErr) {
+			return nil, err
+		}
+		for _, we := range bwErr.WriteErrors {
+			results[modelIndex[we.Index]].Err = we.Err
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"fmt"
+
+	"todo-list-golang/internal/config"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NewTaskRepositoryFromConfig resolves the TaskRepository implementation
+// selected by cfg.Storage.Backend. mongoDB is only used when the backend is
+// "mongo"; the memory and postgres backends manage their own storage and
+// ignore it.
+func NewTaskRepositoryFromConfig(cfg *config.Config, mongoDB *mongo.Database) (TaskRepository, error) {
+	switch cfg.Storage.Backend {
+	case "", "mongo":
+		return NewTaskRepository(mongoDB, cfg.MongoDB.Collection), nil
+	case "memory":
+		return NewMemoryTaskRepository(), nil
+	case "postgres":
+		return NewPostgresTaskRepository(cfg.Storage.Postgres.DSN)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q: use mongo, memory, or postgres", cfg.Storage.Backend)
+	}
+}
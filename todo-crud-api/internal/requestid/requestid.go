@@ -0,0 +1,54 @@
+// Package requestid generates and propagates per-request correlation IDs.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+type contextKey string
+
+const ctxKey contextKey = "requestid.id"
+
+// NewV7 generates a UUIDv7: a time-ordered UUID whose first 48 bits are a
+// millisecond Unix timestamp, making IDs generated later sort after IDs
+// generated earlier. This is preferable to a random UUIDv4 for correlating
+// logs and MongoDB commands in the order requests actually occurred.
+func NewV7() string {
+	var b [16]byte
+
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+
+	if _, err := rand.Read(b[6:]); err != nil {
+		// crypto/rand failing means the system entropy source is broken;
+		// fall back to a timestamp-derived value rather than panicking.
+		binary.BigEndian.PutUint64(b[8:], uint64(time.Now().UnixNano()))
+	}
+
+	// Version 7 in the high nibble of byte 6, variant bits in byte 8.
+	b[6] = (b[6] & 0x0f) | 0x70
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// WithRequestID returns a copy of ctx carrying the request's correlation ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey, id)
+}
+
+// FromContext returns the request ID stashed by middleware.RequestID, or ""
+// if none was set.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey).(string)
+	return id
+}
@@ -0,0 +1,148 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/repository"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultScheduleLease bounds how long a ScheduleWorker holds a leased
+// schedule before another instance is allowed to reclaim it, the same
+// crash-recovery rationale as DefaultLease.
+const DefaultScheduleLease = 30 * time.Second
+
+// ScheduleWorker polls for due Schedules and creates a new Task from each
+// one's TaskTemplate, recording the attempt to JobHistory and purging Tasks
+// past the Schedule's RetentionPolicy. It is a separate subsystem from
+// Worker: Worker re-runs one existing Task via its own Schedule field, this
+// one creates a brand new Task document every occurrence - the
+// "auto-create recurring todo items" case.
+type ScheduleWorker struct {
+	schedules repository.ScheduleRepository
+	tasks     *mongo.Collection
+	history   repository.JobHistoryRepository
+
+	pollInterval time.Duration
+	lease        time.Duration
+}
+
+// NewScheduleWorker creates a ScheduleWorker polling schedules every pollInterval.
+func NewScheduleWorker(schedules repository.ScheduleRepository, tasksCollection *mongo.Collection, history repository.JobHistoryRepository, pollInterval time.Duration) *ScheduleWorker {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &ScheduleWorker{
+		schedules:    schedules,
+		tasks:        tasksCollection,
+		history:      history,
+		pollInterval: pollInterval,
+		lease:        DefaultScheduleLease,
+	}
+}
+
+// Run polls for due schedules until ctx is canceled.
+func (w *ScheduleWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue leases and executes every currently-due schedule in a row, so a
+// burst of simultaneously-due schedules doesn't wait for the next poll tick.
+func (w *ScheduleWorker) runDue(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		schedule, err := w.schedules.LeaseNextDue(ctx, w.lease)
+		if err != nil {
+			log.Printf("[scheduler] error leasing next due schedule: %v", err)
+			return
+		}
+		if schedule == nil {
+			return
+		}
+
+		w.execute(ctx, schedule)
+	}
+}
+
+// execute creates a Task from schedule's template, records the attempt to
+// JobHistory, applies the schedule's RetentionPolicy, and reschedules it for
+// its next cron occurrence.
+func (w *ScheduleWorker) execute(ctx context.Context, schedule *models.Schedule) {
+	entry := &models.JobHistory{
+		ScheduleID: schedule.ID,
+		Kind:       models.JobKindScheduleCreate,
+		StartedAt:  time.Now(),
+	}
+	if err := w.history.Start(ctx, entry); err != nil {
+		log.Printf("[scheduler] error starting history entry for schedule %s: %v", schedule.ID.Hex(), err)
+	}
+
+	task := schedule.Template.ToTask(schedule.ID)
+	_, err := w.tasks.InsertOne(ctx, task)
+
+	status := models.JobStatusSucceeded
+	if err != nil {
+		status = models.JobStatusFailed
+		log.Printf("[scheduler] schedule %s failed to create task: %v", schedule.ID.Hex(), err)
+	} else {
+		entry.TaskID = task.ID
+	}
+
+	if finishErr := w.history.Finish(ctx, entry.ID, status, err); finishErr != nil {
+		log.Printf("[scheduler] error finishing history entry for schedule %s: %v", schedule.ID.Hex(), finishErr)
+	}
+
+	if schedule.Retention != nil {
+		if err := w.applyRetention(ctx, schedule); err != nil {
+			log.Printf("[scheduler] error applying retention for schedule %s: %v", schedule.ID.Hex(), err)
+		}
+	}
+
+	if err := w.reschedule(ctx, schedule); err != nil {
+		log.Printf("[scheduler] error rescheduling schedule %s: %v", schedule.ID.Hex(), err)
+	}
+}
+
+// applyRetention purges Tasks schedule created that have been
+// StatusCompleted for longer than schedule.Retention.After.
+func (w *ScheduleWorker) applyRetention(ctx context.Context, schedule *models.Schedule) error {
+	cutoff := time.Now().Add(-schedule.Retention.After)
+	filter := bson.M{
+		"scheduleId": schedule.ID,
+		"status":     models.StatusCompleted,
+		"updatedAt":  bson.M{"$lt": cutoff},
+	}
+	_, err := w.tasks.DeleteMany(ctx, filter)
+	return err
+}
+
+// reschedule computes schedule's next cron occurrence and clears its lease.
+// If Cron no longer parses, NextRunAt is pushed a pollInterval out so the
+// schedule is retried rather than left stuck on its old lease.
+func (w *ScheduleWorker) reschedule(ctx context.Context, schedule *models.Schedule) error {
+	next, err := cron.ParseStandard(schedule.Cron)
+	if err != nil {
+		return w.schedules.Reschedule(ctx, schedule.ID, time.Now().Add(w.pollInterval))
+	}
+	return w.schedules.Reschedule(ctx, schedule.ID, next.Next(time.Now()))
+}
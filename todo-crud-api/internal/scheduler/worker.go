@@ -0,0 +1,211 @@
+// Package scheduler leases and executes cron-scheduled Tasks, recording
+// each run to the JobHistory collection. It is deliberately separate from
+// the internal/service job worker pool: that subsystem drains one-off
+// enqueued Jobs, while this one re-runs Tasks on their own Schedule.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/repository"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultLease bounds how long a worker holds a leased task before another
+// instance is allowed to reclaim it, the same crash-recovery rationale as
+// repository.DefaultJobLease.
+const DefaultLease = 30 * time.Second
+
+// TaskHandler runs one scheduled execution of task. Returning an error
+// records the JobHistory entry as failed; task is rescheduled for its next
+// cron occurrence either way.
+type TaskHandler func(ctx context.Context, task *models.Task) error
+
+// LogOnlyHandler returns a TaskHandler that simply logs the task it ran,
+// demonstrating the scheduler end-to-end the same way
+// service.TaskAuditJobHandler demonstrates the job worker pool.
+func LogOnlyHandler() TaskHandler {
+	return func(_ context.Context, task *models.Task) error {
+		log.Printf("[scheduler] executed task %s (%s) %q", task.ID.Hex(), task.Kind, task.Name)
+		return nil
+	}
+}
+
+// Worker leases due tasks directly from the tasks collection using
+// findOneAndUpdate on LockedUntil, so multiple app instances can run the
+// scheduler concurrently without double-executing a task.
+type Worker struct {
+	tasks   *mongo.Collection
+	history repository.JobHistoryRepository
+
+	pollInterval time.Duration
+	lease        time.Duration
+
+	mu       sync.Mutex
+	handlers map[string]TaskHandler
+}
+
+// NewWorker creates a Worker polling tasksCollection every pollInterval.
+func NewWorker(tasksCollection *mongo.Collection, history repository.JobHistoryRepository, pollInterval time.Duration) *Worker {
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	return &Worker{
+		tasks:        tasksCollection,
+		history:      history,
+		pollInterval: pollInterval,
+		lease:        DefaultLease,
+		handlers:     make(map[string]TaskHandler),
+	}
+}
+
+// RegisterHandler associates a task Kind with the function that executes it.
+// A due task whose Kind has no registered handler is logged, recorded to
+// JobHistory as a failed run, and rescheduled like any other task.
+func (w *Worker) RegisterHandler(kind string, handler TaskHandler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers[kind] = handler
+}
+
+// Run polls for due tasks until ctx is canceled.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue leases and executes every currently-due task in a row, so a burst
+// of simultaneously-due tasks doesn't wait for the next poll tick.
+func (w *Worker) runDue(ctx context.Context) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		task, err := w.leaseNext(ctx)
+		if err != nil {
+			log.Printf("[scheduler] error leasing next due task: %v", err)
+			return
+		}
+		if task == nil {
+			return
+		}
+
+		w.execute(ctx, task)
+	}
+}
+
+// leaseNext atomically claims the oldest due task: Schedule is set,
+// NextRunAt has elapsed, and LockedUntil is either unset or itself expired
+// (meaning the worker that held it crashed before rescheduling it).
+func (w *Worker) leaseNext(ctx context.Context) (*models.Task, error) {
+	now := time.Now()
+
+	filter := bson.M{
+		"schedule":  bson.M{"$ne": ""},
+		"nextRunAt": bson.M{"$lte": now},
+		"$or": []bson.M{
+			{"lockedUntil": bson.M{"$exists": false}},
+			{"lockedUntil": nil},
+			{"lockedUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"lockedUntil": now.Add(w.lease)},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextRunAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var task models.Task
+	err := w.tasks.FindOneAndUpdate(ctx, filter, update, opts).Decode(&task)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// execute runs task's registered handler, records the result to
+// JobHistory, and reschedules the task for its next cron occurrence.
+func (w *Worker) execute(ctx context.Context, task *models.Task) {
+	w.mu.Lock()
+	handler, ok := w.handlers[task.Kind]
+	w.mu.Unlock()
+
+	entry := &models.JobHistory{
+		TaskID:    task.ID,
+		Kind:      task.Kind,
+		StartedAt: time.Now(),
+	}
+
+	var runErr error
+	if !ok {
+		runErr = fmt.Errorf("no scheduler handler registered for task kind %q", task.Kind)
+	} else {
+		runErr = handler(ctx, task)
+	}
+
+	endedAt := time.Now()
+	entry.EndedAt = &endedAt
+	if runErr != nil {
+		entry.Status = models.JobStatusFailed
+		entry.Error = runErr.Error()
+		log.Printf("[scheduler] task %s (%s) failed: %v", task.ID.Hex(), task.Kind, runErr)
+	} else {
+		entry.Status = models.JobStatusSucceeded
+	}
+
+	if err := w.history.Record(ctx, entry); err != nil {
+		log.Printf("[scheduler] error recording history for task %s: %v", task.ID.Hex(), err)
+	}
+
+	if err := w.reschedule(ctx, task); err != nil {
+		log.Printf("[scheduler] error rescheduling task %s: %v", task.ID.Hex(), err)
+	}
+}
+
+// reschedule computes task's next cron occurrence and clears its lease. If
+// Schedule no longer parses (e.g. it was cleared by an update while the
+// task was running), NextRunAt is cleared instead so the task stops being
+// picked up.
+func (w *Worker) reschedule(ctx context.Context, task *models.Task) error {
+	unset := bson.M{"lockedUntil": ""}
+
+	schedule, err := cron.ParseStandard(task.Schedule)
+	if err != nil {
+		unset["nextRunAt"] = ""
+		_, err = w.tasks.UpdateOne(ctx, bson.M{"_id": task.ID}, bson.M{"$unset": unset})
+		return err
+	}
+
+	update := bson.M{
+		"$set":   bson.M{"nextRunAt": schedule.Next(time.Now())},
+		"$unset": unset,
+	}
+	_, err = w.tasks.UpdateOne(ctx, bson.M{"_id": task.ID}, update)
+	return err
+}
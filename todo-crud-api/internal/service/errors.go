@@ -0,0 +1,115 @@
+package service
+
+import (
+	"errors"
+
+	"todo-list-golang/internal/models"
+)
+
+// Code classifies a ServiceError so callers (and the HTTP handler layer) can
+// react to specific failure modes without string-matching messages.
+type Code string
+
+const (
+	ErrInvalidInput          Code = "invalid_input"
+	ErrNotFound              Code = "not_found"
+	ErrConflict              Code = "conflict"
+	ErrDependencyUnavailable Code = "dependency_unavailable"
+	ErrValidation            Code = "validation"
+)
+
+// ServiceError is the error type returned by the task service's public
+// methods. It carries a machine-readable Code, a human-readable Message,
+// and optional Details for callers that want more context than the message
+// alone (e.g. which field failed validation).
+type ServiceError struct {
+	Code    Code
+	Message string
+	Details map[string]any
+	Err     error // underlying cause, if any; unwrapped by errors.Is/As
+}
+
+func (e *ServiceError) Error() string {
+	return e.Message
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// NewInvalidInputError reports a malformed request, e.g. an ID that isn't a
+// valid ObjectID, or a request with no fields to update.
+func NewInvalidInputError(message string) *ServiceError {
+	return &ServiceError{Code: ErrInvalidInput, Message: message}
+}
+
+// NewValidationError reports a request that parsed fine but fails a business
+// rule, e.g. a name that's too long or a priority outside the allowed set.
+func NewValidationError(message string, details map[string]any) *ServiceError {
+	return &ServiceError{Code: ErrValidation, Message: message, Details: details}
+}
+
+// NewNotFoundError reports that the requested resource doesn't exist.
+func NewNotFoundError(message string, details map[string]any) *ServiceError {
+	return &ServiceError{Code: ErrNotFound, Message: message, Details: details}
+}
+
+// NewConflictError reports that the request collided with another writer,
+// e.g. an optimistic-concurrency version mismatch.
+func NewConflictError(message string) *ServiceError {
+	return &ServiceError{Code: ErrConflict, Message: message}
+}
+
+// NewDependencyUnavailableError reports that a downstream dependency (the
+// repository, typically) failed for reasons unrelated to the request itself.
+func NewDependencyUnavailableError(message string, err error) *ServiceError {
+	return &ServiceError{Code: ErrDependencyUnavailable, Message: message, Err: err}
+}
+
+// IsNotFound reports whether err is a *ServiceError with Code ErrNotFound.
+func IsNotFound(err error) bool {
+	return hasCode(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is a *ServiceError with Code ErrConflict.
+func IsConflict(err error) bool {
+	return hasCode(err, ErrConflict)
+}
+
+// IsValidation reports whether err is a *ServiceError with Code ErrValidation.
+func IsValidation(err error) bool {
+	return hasCode(err, ErrValidation)
+}
+
+// IsInvalidInput reports whether err is a *ServiceError with Code ErrInvalidInput.
+func IsInvalidInput(err error) bool {
+	return hasCode(err, ErrInvalidInput)
+}
+
+// IsDependencyUnavailable reports whether err is a *ServiceError with Code
+// ErrDependencyUnavailable.
+func IsDependencyUnavailable(err error) bool {
+	return hasCode(err, ErrDependencyUnavailable)
+}
+
+func hasCode(err error, code Code) bool {
+	var svcErr *ServiceError
+	if !errors.As(err, &svcErr) {
+		return false
+	}
+	return svcErr.Code == code
+}
+
+// translateRepoError maps a repository error into the matching ServiceError:
+// models.ErrVersionConflict becomes ErrConflict, the pre-existing
+// ErrTaskNotFound string sentinel becomes ErrNotFound, and anything else is
+// treated as a dependency failure.
+func translateRepoError(err error, fallbackMessage string) error {
+	if errors.Is(err, models.ErrVersionConflict) {
+		return NewConflictError(err.Error())
+	}
+	if err.Error() == models.ErrTaskNotFound {
+		return NewNotFoundError(err.Error(), nil)
+	}
+	return NewDependencyUnavailableError(fallbackMessage, err)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultAcquireTimeout is how long AcquireJob long-polls for work before
+// returning with no job.
+const DefaultAcquireTimeout = 5 * time.Second
+
+// acquirePollInterval is how often AcquireJob re-checks the repository while
+// long-polling for a job to become available.
+const acquirePollInterval = 200 * time.Millisecond
+
+// JobService defines the business logic for enqueuing and processing jobs.
+type JobService interface {
+	EnqueueJob(ctx context.Context, kind string, payload bson.M) (*models.Job, error)
+	// AcquireJob long-polls for up to DefaultAcquireTimeout, returning (nil, nil)
+	// if nothing became available in that window.
+	AcquireJob(ctx context.Context, workerID string) (*models.Job, error)
+	CompleteJob(ctx context.Context, jobID primitive.ObjectID) error
+	FailJob(ctx context.Context, jobID primitive.ObjectID, cause error) error
+	HeartbeatJob(ctx context.Context, jobID primitive.ObjectID) error
+	// Subscribe returns a channel of job status events and an unsubscribe func.
+	// This is an in-process fan-out fallback for environments without change
+	// streams (e.g. a standalone mongod); callers on a replica set/sharded
+	// cluster can swap the broadcaster for one backed by collection.Watch.
+	Subscribe() (<-chan models.JobEvent, func())
+}
+
+type jobService struct {
+	repo   repository.JobRepository
+	events *eventBroadcaster
+}
+
+// NewJobService creates a new job service instance.
+func NewJobService(repo repository.JobRepository) JobService {
+	return &jobService{
+		repo:   repo,
+		events: newEventBroadcaster(),
+	}
+}
+
+// EnqueueJob creates a new pending job of the given kind.
+func (s *jobService) EnqueueJob(ctx context.Context, kind string, payload bson.M) (*models.Job, error) {
+	job := &models.Job{
+		Kind:    kind,
+		Payload: payload,
+	}
+
+	if err := s.repo.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	s.events.publish(models.JobEvent{JobID: job.ID, Kind: job.Kind, Status: job.Status, At: time.Now()})
+	return job, nil
+}
+
+// AcquireJob long-polls the repository for the next available job so workers
+// don't need to busy-loop against MongoDB.
+func (s *jobService) AcquireJob(ctx context.Context, workerID string) (*models.Job, error) {
+	deadline := time.Now().Add(DefaultAcquireTimeout)
+	ticker := time.NewTicker(acquirePollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.repo.AcquireNext(ctx, workerID, repository.DefaultJobLease)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			s.events.publish(models.JobEvent{JobID: job.ID, Kind: job.Kind, Status: job.Status, At: time.Now()})
+			return job, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CompleteJob marks a job as succeeded and notifies subscribers.
+func (s *jobService) CompleteJob(ctx context.Context, jobID primitive.ObjectID) error {
+	if err := s.repo.Complete(ctx, jobID); err != nil {
+		return err
+	}
+	s.events.publish(models.JobEvent{JobID: jobID, Status: models.JobStatusSucceeded, At: time.Now()})
+	return nil
+}
+
+// FailJob marks a job as failed and notifies subscribers.
+func (s *jobService) FailJob(ctx context.Context, jobID primitive.ObjectID, cause error) error {
+	if err := s.repo.Fail(ctx, jobID, cause); err != nil {
+		return err
+	}
+	s.events.publish(models.JobEvent{JobID: jobID, Status: models.JobStatusFailed, At: time.Now()})
+	return nil
+}
+
+// HeartbeatJob extends a running job's lease so it isn't reclaimed by another worker.
+func (s *jobService) HeartbeatJob(ctx context.Context, jobID primitive.ObjectID) error {
+	return s.repo.Heartbeat(ctx, jobID, repository.DefaultJobLease)
+}
+
+// Subscribe registers a new listener for job status events.
+func (s *jobService) Subscribe() (<-chan models.JobEvent, func()) {
+	return s.events.subscribe()
+}
+
+// eventBroadcaster fans out job events to any number of subscribers without
+// blocking publishers on slow readers.
+type eventBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan models.JobEvent]struct{}
+}
+
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{subscribers: make(map[chan models.JobEvent]struct{})}
+}
+
+func (b *eventBroadcaster) subscribe() (<-chan models.JobEvent, func()) {
+	ch := make(chan models.JobEvent, 16)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return ch, unsubscribe
+}
+
+func (b *eventBroadcaster) publish(event models.JobEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Slow subscriber: drop the event rather than block the publisher.
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/repository"
+
+	"github.com/robfig/cron/v3"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ScheduleService defines the business logic for creating recurring
+// task-creation Schedules and inspecting their execution history.
+type ScheduleService interface {
+	CreateSchedule(ctx context.Context, req *models.CreateScheduleRequest) (*models.Schedule, error)
+	// GetExecutions returns scheduleID's JobHistory entries (most recent
+	// first) plus an aggregate count by status.
+	GetExecutions(ctx context.Context, scheduleID string) ([]*models.JobHistory, models.ExecutionCounts, error)
+}
+
+type scheduleService struct {
+	repo    repository.ScheduleRepository
+	history repository.JobHistoryRepository
+}
+
+// NewScheduleService creates a new schedule service.
+func NewScheduleService(repo repository.ScheduleRepository, history repository.JobHistoryRepository) ScheduleService {
+	return &scheduleService{repo: repo, history: history}
+}
+
+// CreateSchedule validates req's cron expression, computes its first
+// NextRunAt, and persists the Schedule.
+func (s *scheduleService) CreateSchedule(ctx context.Context, req *models.CreateScheduleRequest) (*models.Schedule, error) {
+	parsed, err := cron.ParseStandard(req.Cron)
+	if err != nil {
+		return nil, NewValidationError("invalid cron expression: "+err.Error(), nil)
+	}
+
+	schedule := req.ToSchedule()
+	schedule.NextRunAt = parsed.Next(time.Now())
+
+	if err := s.repo.Create(ctx, schedule); err != nil {
+		return nil, NewDependencyUnavailableError("error creating schedule", err)
+	}
+
+	return schedule, nil
+}
+
+// GetExecutions returns scheduleID's execution history and aggregate counts.
+func (s *scheduleService) GetExecutions(ctx context.Context, scheduleID string) ([]*models.JobHistory, models.ExecutionCounts, error) {
+	id, err := primitive.ObjectIDFromHex(scheduleID)
+	if err != nil {
+		return nil, models.ExecutionCounts{}, NewInvalidInputError("invalid schedule id")
+	}
+
+	history, err := s.history.FindByScheduleID(ctx, id)
+	if err != nil {
+		return nil, models.ExecutionCounts{}, NewDependencyUnavailableError("error fetching schedule executions", err)
+	}
+
+	counts, err := s.history.CountByScheduleID(ctx, id)
+	if err != nil {
+		return nil, models.ExecutionCounts{}, NewDependencyUnavailableError("error counting schedule executions", err)
+	}
+
+	return history, counts, nil
+}
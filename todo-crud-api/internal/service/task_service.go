@@ -4,23 +4,51 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"todo-list-golang/internal/audit"
 	"todo-list-golang/internal/models"
 	"todo-list-golang/internal/repository"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// maxUpdateRetries bounds how many attempts UpdateTask makes in all,
+// re-reading and re-applying the request after each lost
+// optimistic-concurrency race.
+const maxUpdateRetries = 3
+
+// toggleLockName serializes ToggleTaskCompletion's read-modify-write for a
+// given task across replicas. WithLock scopes it per-task.
+func toggleLockName(id primitive.ObjectID) string {
+	return "task-toggle:" + id.Hex()
+}
+
 // TaskService defines the interface for business logic
 type TaskService interface {
 	CreateTask(ctx context.Context, req *models.CreateTaskRequest) (*models.Task, error)
-	GetAllTasks(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error)
+	GetAllTasks(ctx context.Context, filter *models.TaskFilter, pagination *models.Pagination) (tasks []*models.Task, out *models.Pagination, err error)
 	GetTaskByID(ctx context.Context, id string) (*models.Task, error)
 	UpdateTask(ctx context.Context, id string, req *models.UpdateTaskRequest) (*models.Task, error)
 	DeleteTask(ctx context.Context, id string) error
 	ToggleTaskCompletion(ctx context.Context, id string) (*models.Task, error)
+	PauseTask(ctx context.Context, id, reason string) (*models.Task, error)
+	ResumeTask(ctx context.Context, id string) (*models.Task, error)
 	GetTaskStats(ctx context.Context) (*TaskStats, error)
+	BulkCreateTasks(ctx context.Context, reqs []*models.CreateTaskRequest) (tasks []*models.Task, failures []BulkFailure, err error)
+	BulkToggleTasks(ctx context.Context, ids []string) (tasks []*models.Task, failures []BulkFailure, err error)
+	BulkDeleteTasks(ctx context.Context, ids []string) (failures []BulkFailure, err error)
+}
+
+// BulkFailure reports why one item of a bulk request didn't succeed. ID is
+// the task's ID (assigned even on a failed create, so callers can still
+// report it) or, for BulkToggleTasks/BulkDeleteTasks, the ID the caller sent.
+type BulkFailure struct {
+	ID    string
+	Error string
 }
 
 // TaskStats represents task statistics
@@ -28,18 +56,98 @@ type TaskStats struct {
 	Total        int64 `json:"total"`
 	Completed    int64 `json:"completed"`
 	Pending      int64 `json:"pending"`
+	Paused       int64 `json:"paused"`
 	HighPriority int64 `json:"highPriority"`
 }
 
 // taskService implements TaskService
 type taskService struct {
-	repo repository.TaskRepository
+	repo    repository.TaskRepository
+	jobs    JobService         // optional: enqueues audit jobs when configured
+	locks   *repository.Locker // optional: serializes ToggleTaskCompletion across replicas
+	auditor audit.Auditor      // optional: records Create/Update/Delete to the audit log
+}
+
+// TaskServiceOption configures optional dependencies on NewTaskService.
+type TaskServiceOption func(*taskService)
+
+// WithJobService wires a JobService so task mutations enqueue audit jobs.
+// It is optional: callers that don't need the job subsystem can omit it.
+func WithJobService(jobs JobService) TaskServiceOption {
+	return func(s *taskService) {
+		s.jobs = jobs
+	}
+}
+
+// WithLocker wires a Locker so ToggleTaskCompletion serializes its
+// read-modify-write across replicas. It is optional: without it, toggling
+// still works but relies solely on optimistic-concurrency conflicts.
+func WithLocker(locks *repository.Locker) TaskServiceOption {
+	return func(s *taskService) {
+		s.locks = locks
+	}
+}
+
+// WithAuditor wires an Auditor so Create/Update/Delete record a before/after
+// entry to the audit log. It is optional: without it, mutations proceed
+// unaudited.
+func WithAuditor(auditor audit.Auditor) TaskServiceOption {
+	return func(s *taskService) {
+		s.auditor = auditor
+	}
 }
 
 // NewTaskService creates a new service instance
-func NewTaskService(repo repository.TaskRepository) TaskService {
-	return &taskService{
-		repo: repo,
+func NewTaskService(repo repository.TaskRepository, opts ...TaskServiceOption) TaskService {
+	s := &taskService{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// enqueueAuditJob best-effort enqueues a task-audit job. Failures are logged
+// rather than propagated: auditing must never block the task endpoints that
+// demonstrate it.
+func (s *taskService) enqueueAuditJob(ctx context.Context, action string, task *models.Task) {
+	if s.jobs == nil {
+		return
+	}
+
+	payload := bson.M{
+		"action": action,
+		"taskId": task.ID.Hex(),
+	}
+	if _, err := s.jobs.EnqueueJob(ctx, models.JobKindTaskAudit, payload); err != nil {
+		log.Printf("Error enqueueing audit job for task %s: %v", task.ID.Hex(), err)
+	}
+}
+
+// recordAudit writes a before/after diff to the audit log. Failures are
+// logged rather than propagated, same rationale as enqueueAuditJob: auditing
+// must never block the task endpoints it's observing.
+func (s *taskService) recordAudit(ctx context.Context, action, resourceID string, before, after any) {
+	if s.auditor == nil {
+		return
+	}
+
+	beforeDiff, afterDiff, err := audit.Diff(before, after)
+	if err != nil {
+		log.Printf("Error diffing task %s for audit: %v", resourceID, err)
+		return
+	}
+
+	entry := audit.Entry{
+		Action:     action,
+		Resource:   "task",
+		ResourceID: resourceID,
+		DoerID:     audit.DoerID(ctx),
+		Before:     beforeDiff,
+		After:      afterDiff,
+		RequestID:  audit.TraceID(ctx),
+	}
+	if err := s.auditor.Record(ctx, entry); err != nil {
+		log.Printf("Error recording audit entry for task %s: %v", resourceID, err)
 	}
 }
 
@@ -47,30 +155,63 @@ func NewTaskService(repo repository.TaskRepository) TaskService {
 func (s *taskService) CreateTask(ctx context.Context, req *models.CreateTaskRequest) (*models.Task, error) {
 	// Convert the request to Task
 	task := req.ToTask()
+	task.DoerID = audit.DoerID(ctx)
+	task.OwnerID = task.DoerID
+	task.TraceID = audit.TraceID(ctx)
 
 	// Validate the task
 	if err := task.Validate(); err != nil {
-		return nil, err
+		return nil, NewValidationError(err.Error(), nil)
 	}
 
 	// Save to repository
 	if err := s.repo.Create(ctx, task); err != nil {
 		log.Printf("Error creating task: %v", err)
-		return nil, errors.New("error creating task")
+		return nil, NewDependencyUnavailableError("error creating task", err)
 	}
 
+	s.enqueueAuditJob(ctx, "create", task)
+	s.recordAudit(ctx, "create", task.ID.Hex(), nil, task)
 	return task, nil
 }
 
-// GetAllTasks returns all tasks
-func (s *taskService) GetAllTasks(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error) {
-	tasks, err := s.repo.FindAll(ctx, filter)
+// GetAllTasks returns tasks matching filter, ordered and paged per
+// pagination (page_size becomes the page's Limit; page is not read here and
+// is only passed back for the caller's Link bookkeeping, since the
+// repository walks forward by indexed cursor, never skip). pagination may be nil, in which case
+// filter's own Sort/Cursor/Limit are used unchanged. The returned
+// Pagination is pagination with TotalCount and NextCursor filled in.
+func (s *taskService) GetAllTasks(ctx context.Context, filter *models.TaskFilter, pagination *models.Pagination) ([]*models.Task, *models.Pagination, error) {
+	if pagination != nil {
+		filter.Sort = pagination.Sort
+		filter.Cursor = pagination.Cursor
+		if pagination.PageSize > 0 {
+			filter.Limit = pagination.PageSize
+		}
+	}
+
+	tasks, nextCursor, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, nil, NewInvalidInputError(err.Error())
+		}
 		log.Printf("Error fetching tasks: %v", err)
-		return nil, errors.New("error fetching tasks")
+		return nil, nil, errors.New("error fetching tasks")
+	}
+
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		log.Printf("Error counting tasks: %v", err)
+		return nil, nil, errors.New("error fetching tasks")
+	}
+
+	if pagination == nil {
+		pagination = &models.Pagination{}
 	}
+	pagination.TotalCount = total
+	pagination.NextCursor = nextCursor
 
-	return tasks, nil
+	return tasks, pagination, nil
 }
 
 // GetTaskByID returns a specific task
@@ -78,52 +219,83 @@ func (s *taskService) GetTaskByID(ctx context.Context, id string) (*models.Task,
 	// Convert string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid ID")
+		return nil, NewInvalidInputError("invalid task id")
 	}
 
 	task, err := s.repo.FindByID(ctx, objID)
 	if err != nil {
 		log.Printf("Error fetching task: %v", err)
-		return nil, err
+		return nil, translateRepoError(err, "error fetching task")
 	}
 
 	return task, nil
 }
 
-// UpdateTask updates an existing task
+// UpdateTask updates an existing task. By default it makes up to
+// maxUpdateRetries attempts in all on optimistic-concurrency conflicts, re-reading
+// the task, re-applying req, and re-validating each time, so a request only
+// fails if it keeps losing the race. If req.ExpectedVersion is set, that
+// behavior is disabled: the caller has already read a specific version and
+// wants to know if it lost the race, not have its update silently replayed
+// against whatever is latest, so a mismatch fails immediately with a
+// conflict.
 func (s *taskService) UpdateTask(ctx context.Context, id string, req *models.UpdateTaskRequest) (*models.Task, error) {
 	// Check if there are updates
 	if !req.HasUpdates() {
-		return nil, errors.New("no updates provided")
+		return nil, NewInvalidInputError("no updates provided")
 	}
 
 	// Convert string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid ID")
+		return nil, NewInvalidInputError("invalid task id")
 	}
 
-	// Find the existing task
-	task, err := s.repo.FindByID(ctx, objID)
-	if err != nil {
-		return nil, err
+	maxAttempts := maxUpdateRetries
+	if req.ExpectedVersion != nil {
+		maxAttempts = 1
 	}
 
-	// Apply the updates
-	req.ApplyUpdates(task)
-
-	// Validate the updated task
-	if err := task.Validate(); err != nil {
-		return nil, err
+	var task *models.Task
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		task, err = s.repo.FindByID(ctx, objID)
+		if err != nil {
+			return nil, translateRepoError(err, "error fetching task")
+		}
+
+		if req.ExpectedVersion != nil && *req.ExpectedVersion != task.Version {
+			return nil, NewConflictError(fmt.Sprintf(
+				"error updating task: expected version %d but task is at version %d",
+				*req.ExpectedVersion, task.Version))
+		}
+
+		before := *task
+		expectedVersion := task.Version
+		req.ApplyUpdates(task)
+		task.DoerID = audit.DoerID(ctx)
+		task.TraceID = audit.TraceID(ctx)
+
+		if err := task.Validate(); err != nil {
+			return nil, NewValidationError(err.Error(), nil)
+		}
+
+		err = s.repo.Update(ctx, objID, task, expectedVersion)
+		if err == nil {
+			s.recordAudit(ctx, "update", task.ID.Hex(), &before, task)
+			return task, nil
+		}
+		if !errors.Is(err, models.ErrVersionConflict) {
+			log.Printf("Error updating task: %v", err)
+			return nil, NewDependencyUnavailableError("error updating task", err)
+		}
+		log.Printf("Version conflict updating task %s, retrying (attempt %d)", id, attempt+1)
 	}
 
-	// Save the changes
-	if err := s.repo.Update(ctx, objID, task); err != nil {
-		log.Printf("Error updating task: %v", err)
-		return nil, errors.New("error updating task")
+	return nil, &ServiceError{
+		Code:    ErrConflict,
+		Message: fmt.Sprintf("error updating task: %v after %d attempts", models.ErrVersionConflict, maxAttempts),
+		Err:     models.ErrVersionConflict,
 	}
-
-	return task, nil
 }
 
 // DeleteTask removes a task
@@ -131,40 +303,133 @@ func (s *taskService) DeleteTask(ctx context.Context, id string) error {
 	// Convert string to ObjectID
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid ID")
+		return NewInvalidInputError("invalid task id")
+	}
+
+	task, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return translateRepoError(err, "error fetching task")
 	}
 
 	if err := s.repo.Delete(ctx, objID); err != nil {
 		log.Printf("Error deleting task: %v", err)
-		return err
+		return translateRepoError(err, "error deleting task")
 	}
 
+	s.enqueueAuditJob(ctx, "delete", task)
+	s.recordAudit(ctx, "delete", task.ID.Hex(), task, nil)
 	return nil
 }
 
-// ToggleTaskCompletion toggles the completion status of a task
+// ToggleTaskCompletion toggles the completion status of a task. When a
+// Locker is configured, the read-modify-write is serialized across
+// replicas by holding an advisory lock scoped to this task's ID.
 func (s *taskService) ToggleTaskCompletion(ctx context.Context, id string) (*models.Task, error) {
 	// Convert string to ObjectID
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, NewInvalidInputError("invalid task id")
+	}
+
+	var task *models.Task
+	toggle := func(ctx context.Context) error {
+		var err error
+		task, err = s.repo.FindByID(ctx, objID)
+		if err != nil {
+			return err
+		}
+
+		expectedVersion := task.Version
+		if task.Status == models.StatusCompleted {
+			task.Status = models.StatusActive
+		} else {
+			task.Status = models.StatusCompleted
+		}
+		task.Completed = task.Status == models.StatusCompleted
+		task.UpdatedAt = time.Now()
+		task.Version++
+		task.DoerID = audit.DoerID(ctx)
+		task.TraceID = audit.TraceID(ctx)
+
+		return s.repo.Update(ctx, objID, task, expectedVersion)
+	}
+
+	if s.locks != nil {
+		err = s.locks.WithLock(ctx, toggleLockName(objID), toggle)
+	} else {
+		err = toggle(ctx)
+	}
+	if err != nil {
+		if err.Error() == models.ErrTaskNotFound || errors.Is(err, models.ErrVersionConflict) {
+			return nil, translateRepoError(err, "error updating task")
+		}
+		log.Printf("Error updating task: %v", err)
+		return nil, NewDependencyUnavailableError("error updating task", err)
+	}
+
+	s.enqueueAuditJob(ctx, "toggle", task)
+	return task, nil
+}
+
+// PauseTask transitions a task to paused, recording an optional reason.
+func (s *taskService) PauseTask(ctx context.Context, id, reason string) (*models.Task, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, errors.New("invalid ID")
 	}
 
-	// Find the existing task
 	task, err := s.repo.FindByID(ctx, objID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Toggle the status
-	task.Completed = !task.Completed
+	expectedVersion := task.Version
+	if err := task.Pause(reason); err != nil {
+		return nil, err
+	}
+	task.DoerID = audit.DoerID(ctx)
+	task.TraceID = audit.TraceID(ctx)
+
+	if err := s.repo.Update(ctx, objID, task, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, err
+		}
+		log.Printf("Error pausing task: %v", err)
+		return nil, errors.New("error pausing task")
+	}
 
-	// Save the changes
-	if err := s.repo.Update(ctx, objID, task); err != nil {
-		log.Printf("Error updating task: %v", err)
-		return nil, errors.New("error updating task")
+	s.enqueueAuditJob(ctx, "pause", task)
+	return task, nil
+}
+
+// ResumeTask transitions a paused task back to active.
+func (s *taskService) ResumeTask(ctx context.Context, id string) (*models.Task, error) {
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, errors.New("invalid ID")
 	}
 
+	task, err := s.repo.FindByID(ctx, objID)
+	if err != nil {
+		return nil, err
+	}
+
+	expectedVersion := task.Version
+	if err := task.Resume(); err != nil {
+		return nil, err
+	}
+	task.DoerID = audit.DoerID(ctx)
+	task.TraceID = audit.TraceID(ctx)
+
+	if err := s.repo.Update(ctx, objID, task, expectedVersion); err != nil {
+		if errors.Is(err, models.ErrVersionConflict) {
+			return nil, err
+		}
+		log.Printf("Error resuming task: %v", err)
+		return nil, errors.New("error resuming task")
+	}
+
+	s.enqueueAuditJob(ctx, "resume", task)
 	return task, nil
 }
 
@@ -196,10 +461,128 @@ func (s *taskService) GetTaskStats(ctx context.Context) (*TaskStats, error) {
 		return nil, err
 	}
 
+	// Paused tasks
+	paused, err := s.repo.Count(ctx, &models.TaskFilter{Status: models.StatusPaused})
+	if err != nil {
+		return nil, err
+	}
+
 	return &TaskStats{
 		Total:        total,
 		Completed:    completed,
 		Pending:      pending,
+		Paused:       paused,
 		HighPriority: highPriority,
 	}, nil
 }
+
+// BulkCreateTasks validates and converts reqs to tasks, then inserts them in
+// a single repository-level bulk call: one invalid request fails only its
+// own entry, so a caller submitting 100 tasks where one is malformed still
+// gets the other 99 created.
+func (s *taskService) BulkCreateTasks(ctx context.Context, reqs []*models.CreateTaskRequest) ([]*models.Task, []BulkFailure, error) {
+	doerID := audit.DoerID(ctx)
+	traceID := audit.TraceID(ctx)
+
+	candidates := make([]*models.Task, 0, len(reqs))
+	var failures []BulkFailure
+	for _, req := range reqs {
+		task := req.ToTask()
+		task.DoerID = doerID
+		task.OwnerID = doerID
+		task.TraceID = traceID
+
+		if err := task.Validate(); err != nil {
+			failures = append(failures, BulkFailure{ID: task.Name, Error: err.Error()})
+			continue
+		}
+		candidates = append(candidates, task)
+	}
+
+	if len(candidates) == 0 {
+		return nil, failures, nil
+	}
+
+	results, err := s.repo.BulkCreate(ctx, candidates)
+	if err != nil {
+		log.Printf("Error bulk creating tasks: %v", err)
+		return nil, nil, NewDependencyUnavailableError("error creating tasks", err)
+	}
+
+	tasks := make([]*models.Task, 0, len(candidates))
+	for i, result := range results {
+		task := candidates[i]
+		if result.Err != nil {
+			failures = append(failures, BulkFailure{ID: result.ID, Error: result.Err.Error()})
+			continue
+		}
+		s.enqueueAuditJob(ctx, "create", task)
+		s.recordAudit(ctx, "create", task.ID.Hex(), nil, task)
+		tasks = append(tasks, task)
+	}
+
+	return tasks, failures, nil
+}
+
+// BulkToggleTasks toggles each id in turn by calling ToggleTaskCompletion,
+// so every toggle keeps the same per-task Locker serialization and
+// optimistic-concurrency check as the single-item endpoint rather than
+// going around it with a raw bulk update. One id failing doesn't stop the
+// rest from being attempted.
+func (s *taskService) BulkToggleTasks(ctx context.Context, ids []string) ([]*models.Task, []BulkFailure, error) {
+	tasks := make([]*models.Task, 0, len(ids))
+	var failures []BulkFailure
+	for _, id := range ids {
+		task, err := s.ToggleTaskCompletion(ctx, id)
+		if err != nil {
+			failures = append(failures, BulkFailure{ID: id, Error: err.Error()})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, failures, nil
+}
+
+// BulkDeleteTasks deletes the tasks named by ids via a single
+// repository-level bulk call, so one missing or already-deleted id doesn't
+// stop the rest from being removed.
+func (s *taskService) BulkDeleteTasks(ctx context.Context, ids []string) ([]BulkFailure, error) {
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	var failures []BulkFailure
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			failures = append(failures, BulkFailure{ID: id, Error: "invalid task id"})
+			continue
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	if len(objIDs) == 0 {
+		return failures, nil
+	}
+
+	results, err := s.repo.BulkDelete(ctx, objIDs)
+	if err != nil {
+		log.Printf("Error bulk deleting tasks: %v", err)
+		return nil, NewDependencyUnavailableError("error deleting tasks", err)
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			failures = append(failures, BulkFailure{ID: result.ID, Error: result.Err.Error()})
+			continue
+		}
+		s.enqueueAuditJob(ctx, "delete", &models.Task{ID: mustObjectID(result.ID)})
+	}
+
+	return failures, nil
+}
+
+// mustObjectID parses hex into an ObjectID for enqueueAuditJob, which only
+// needs task.ID. hex was produced by primitive.ObjectID.Hex() a few lines
+// up, so it's always valid.
+func mustObjectID(hex string) primitive.ObjectID {
+	id, _ := primitive.ObjectIDFromHex(hex)
+	return id
+}
@@ -7,19 +7,20 @@ import (
 	"time"
 
 	"todo-list-golang/internal/models"
+	"todo-list-golang/internal/repository"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Mock Repository para testes
 type mockTaskRepository struct {
-	tasks          map[primitive.ObjectID]*models.Task
-	createFunc     func(ctx context.Context, task *models.Task) error
-	findAllFunc    func(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error)
-	findByIDFunc   func(ctx context.Context, id primitive.ObjectID) (*models.Task, error)
-	updateFunc     func(ctx context.Context, id primitive.ObjectID, task *models.Task) error
-	deleteFunc     func(ctx context.Context, id primitive.ObjectID) error
-	countFunc      func(ctx context.Context, filter *models.TaskFilter) (int64, error)
+	tasks        map[primitive.ObjectID]*models.Task
+	createFunc   func(ctx context.Context, task *models.Task) error
+	findAllFunc  func(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, string, error)
+	findByIDFunc func(ctx context.Context, id primitive.ObjectID) (*models.Task, error)
+	updateFunc   func(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error
+	deleteFunc   func(ctx context.Context, id primitive.ObjectID) error
+	countFunc    func(ctx context.Context, filter *models.TaskFilter) (int64, error)
 }
 
 func (m *mockTaskRepository) Create(ctx context.Context, task *models.Task) error {
@@ -34,7 +35,7 @@ func (m *mockTaskRepository) Create(ctx context.Context, task *models.Task) erro
 	return nil
 }
 
-func (m *mockTaskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, error) {
+func (m *mockTaskRepository) FindAll(ctx context.Context, filter *models.TaskFilter) ([]*models.Task, string, error) {
 	if m.findAllFunc != nil {
 		return m.findAllFunc(ctx, filter)
 	}
@@ -42,7 +43,7 @@ func (m *mockTaskRepository) FindAll(ctx context.Context, filter *models.TaskFil
 	for _, task := range m.tasks {
 		tasks = append(tasks, task)
 	}
-	return tasks, nil
+	return tasks, "", nil
 }
 
 func (m *mockTaskRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
@@ -51,17 +52,25 @@ func (m *mockTaskRepository) FindByID(ctx context.Context, id primitive.ObjectID
 	}
 	task, exists := m.tasks[id]
 	if !exists {
-		return nil, errors.New("task not found")
+		return nil, errors.New(models.ErrTaskNotFound)
 	}
+	// Hand out a copy, as the real repositories do, so the service's
+	// in-place edits don't reach the stored task before Update runs.
+	clone := *task
+	task = &clone
 	return task, nil
 }
 
-func (m *mockTaskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task) error {
+func (m *mockTaskRepository) Update(ctx context.Context, id primitive.ObjectID, task *models.Task, expectedVersion int) error {
 	if m.updateFunc != nil {
-		return m.updateFunc(ctx, id, task)
+		return m.updateFunc(ctx, id, task, expectedVersion)
 	}
-	if _, exists := m.tasks[id]; !exists {
-		return errors.New("task not found")
+	existing, exists := m.tasks[id]
+	if !exists {
+		return errors.New(models.ErrTaskNotFound)
+	}
+	if existing.Version != expectedVersion {
+		return models.ErrVersionConflict
 	}
 	m.tasks[id] = task
 	return nil
@@ -72,12 +81,29 @@ func (m *mockTaskRepository) Delete(ctx context.Context, id primitive.ObjectID)
 		return m.deleteFunc(ctx, id)
 	}
 	if _, exists := m.tasks[id]; !exists {
-		return errors.New("task not found")
+		return errors.New(models.ErrTaskNotFound)
 	}
 	delete(m.tasks, id)
 	return nil
 }
 
+func (m *mockTaskRepository) BulkCreate(ctx context.Context, tasks []*models.Task) ([]repository.BulkResult, error) {
+	results := make([]repository.BulkResult, len(tasks))
+	for i, task := range tasks {
+		err := m.Create(ctx, task)
+		results[i] = repository.BulkResult{ID: task.ID.Hex(), Err: err}
+	}
+	return results, nil
+}
+
+func (m *mockTaskRepository) BulkDelete(ctx context.Context, ids []primitive.ObjectID) ([]repository.BulkResult, error) {
+	results := make([]repository.BulkResult, len(ids))
+	for i, id := range ids {
+		results[i] = repository.BulkResult{ID: id.Hex(), Err: m.Delete(ctx, id)}
+	}
+	return results, nil
+}
+
 func (m *mockTaskRepository) Count(ctx context.Context, filter *models.TaskFilter) (int64, error) {
 	if m.countFunc != nil {
 		return m.countFunc(ctx, filter)
@@ -90,9 +116,9 @@ func TestCreateTask(t *testing.T) {
 	service := NewTaskService(mockRepo)
 
 	tests := []struct {
-		name    string
-		req     *models.CreateTaskRequest
-		wantErr bool
+		name     string
+		req      *models.CreateTaskRequest
+		wantCode Code // zero value means no error expected
 	}{
 		{
 			name: "valid task",
@@ -100,7 +126,6 @@ func TestCreateTask(t *testing.T) {
 				Name:     "Test Task",
 				Priority: "medium",
 			},
-			wantErr: false,
 		},
 		{
 			name: "empty name",
@@ -108,7 +133,7 @@ func TestCreateTask(t *testing.T) {
 				Name:     "",
 				Priority: "medium",
 			},
-			wantErr: true,
+			wantCode: ErrValidation,
 		},
 		{
 			name: "invalid priority",
@@ -116,18 +141,18 @@ func TestCreateTask(t *testing.T) {
 				Name:     "Test",
 				Priority: "invalid",
 			},
-			wantErr: true,
+			wantCode: ErrValidation,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task, err := service.CreateTask(context.Background(), tt.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("CreateTask() error = %v, wantErr %v", err, tt.wantErr)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("CreateTask() error code = %q, want %q (err = %v)", code, tt.wantCode, err)
 				return
 			}
-			if !tt.wantErr && task == nil {
+			if tt.wantCode == "" && task == nil {
 				t.Error("Expected task to be created")
 			}
 		})
@@ -149,35 +174,34 @@ func TestGetTaskByID(t *testing.T) {
 	service := NewTaskService(mockRepo)
 
 	tests := []struct {
-		name    string
-		id      string
-		wantErr bool
+		name     string
+		id       string
+		wantCode Code // zero value means no error expected
 	}{
 		{
-			name:    "valid id",
-			id:      testID.Hex(),
-			wantErr: false,
+			name: "valid id",
+			id:   testID.Hex(),
 		},
 		{
-			name:    "invalid id format",
-			id:      "invalid",
-			wantErr: true,
+			name:     "invalid id format",
+			id:       "invalid",
+			wantCode: ErrInvalidInput,
 		},
 		{
-			name:    "non-existent id",
-			id:      primitive.NewObjectID().Hex(),
-			wantErr: true,
+			name:     "non-existent id",
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: ErrNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task, err := service.GetTaskByID(context.Background(), tt.id)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("GetTaskByID() error = %v, wantErr %v", err, tt.wantErr)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("GetTaskByID() error code = %q, want %q (err = %v)", code, tt.wantCode, err)
 				return
 			}
-			if !tt.wantErr && task == nil {
+			if tt.wantCode == "" && task == nil {
 				t.Error("Expected task to be returned")
 			}
 		})
@@ -202,10 +226,10 @@ func TestUpdateTask(t *testing.T) {
 	service := NewTaskService(mockRepo)
 
 	tests := []struct {
-		name    string
-		id      string
-		req     *models.UpdateTaskRequest
-		wantErr bool
+		name     string
+		id       string
+		req      *models.UpdateTaskRequest
+		wantCode Code // zero value means no error expected
 	}{
 		{
 			name: "valid update",
@@ -213,13 +237,12 @@ func TestUpdateTask(t *testing.T) {
 			req: &models.UpdateTaskRequest{
 				Name: stringPtr("Updated Name"),
 			},
-			wantErr: false,
 		},
 		{
-			name:    "no updates",
-			id:      testID.Hex(),
-			req:     &models.UpdateTaskRequest{},
-			wantErr: true,
+			name:     "no updates",
+			id:       testID.Hex(),
+			req:      &models.UpdateTaskRequest{},
+			wantCode: ErrInvalidInput,
 		},
 		{
 			name: "invalid id",
@@ -227,7 +250,7 @@ func TestUpdateTask(t *testing.T) {
 			req: &models.UpdateTaskRequest{
 				Name: stringPtr("Updated"),
 			},
-			wantErr: true,
+			wantCode: ErrInvalidInput,
 		},
 		{
 			name: "non-existent task",
@@ -235,18 +258,18 @@ func TestUpdateTask(t *testing.T) {
 			req: &models.UpdateTaskRequest{
 				Name: stringPtr("Updated"),
 			},
-			wantErr: true,
+			wantCode: ErrNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			task, err := service.UpdateTask(context.Background(), tt.id, tt.req)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("UpdateTask() error = %v, wantErr %v", err, tt.wantErr)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("UpdateTask() error code = %q, want %q (err = %v)", code, tt.wantCode, err)
 				return
 			}
-			if !tt.wantErr && task == nil {
+			if tt.wantCode == "" && task == nil {
 				t.Error("Expected updated task to be returned")
 			}
 		})
@@ -309,32 +332,31 @@ func TestDeleteTask(t *testing.T) {
 	service := NewTaskService(mockRepo)
 
 	tests := []struct {
-		name    string
-		id      string
-		wantErr bool
+		name     string
+		id       string
+		wantCode Code // zero value means no error expected
 	}{
 		{
-			name:    "valid deletion",
-			id:      testID.Hex(),
-			wantErr: false,
+			name: "valid deletion",
+			id:   testID.Hex(),
 		},
 		{
-			name:    "invalid id",
-			id:      "invalid",
-			wantErr: true,
+			name:     "invalid id",
+			id:       "invalid",
+			wantCode: ErrInvalidInput,
 		},
 		{
-			name:    "non-existent task",
-			id:      primitive.NewObjectID().Hex(),
-			wantErr: true,
+			name:     "non-existent task",
+			id:       primitive.NewObjectID().Hex(),
+			wantCode: ErrNotFound,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := service.DeleteTask(context.Background(), tt.id)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("DeleteTask() error = %v, wantErr %v", err, tt.wantErr)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("DeleteTask() error code = %q, want %q (err = %v)", code, tt.wantCode, err)
 			}
 		})
 	}
@@ -384,7 +406,112 @@ func TestGetTaskStats(t *testing.T) {
 	}
 }
 
+func TestUpdateTaskRetriesOnVersionConflict(t *testing.T) {
+	testID := primitive.NewObjectID()
+	task := &models.Task{
+		ID:        testID,
+		Name:      "Original Name",
+		Priority:  "low",
+		Version:   1,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+
+	var updateAttempts int
+	mockRepo := &mockTaskRepository{
+		findByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+			clone := *task
+			return &clone, nil
+		},
+		updateFunc: func(ctx context.Context, id primitive.ObjectID, updated *models.Task, expectedVersion int) error {
+			updateAttempts++
+			if updateAttempts == 1 {
+				// Simulate another writer winning the race on the first attempt.
+				task.Version = 2
+				return models.ErrVersionConflict
+			}
+			task.Name = updated.Name
+			task.Version = updated.Version
+			return nil
+		},
+	}
+
+	service := NewTaskService(mockRepo)
+
+	updated, err := service.UpdateTask(context.Background(), testID.Hex(), &models.UpdateTaskRequest{
+		Name: stringPtr("Updated Name"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateTask() unexpected error = %v", err)
+	}
+	if updateAttempts != 2 {
+		t.Errorf("expected UpdateTask to retry once, got %d attempts", updateAttempts)
+	}
+	if updated.Name != "Updated Name" {
+		t.Errorf("expected name to be updated after retry, got %q", updated.Name)
+	}
+}
+
+func TestUpdateTaskGivesUpAfterMaxRetries(t *testing.T) {
+	testID := primitive.NewObjectID()
+	mockRepo := &mockTaskRepository{
+		findByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+			return &models.Task{ID: testID, Name: "Original Name", Priority: "low", Version: 1}, nil
+		},
+		updateFunc: func(ctx context.Context, id primitive.ObjectID, updated *models.Task, expectedVersion int) error {
+			return models.ErrVersionConflict
+		},
+	}
+
+	service := NewTaskService(mockRepo)
+
+	_, err := service.UpdateTask(context.Background(), testID.Hex(), &models.UpdateTaskRequest{
+		Name: stringPtr("Updated Name"),
+	})
+	if !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("expected a wrapped ErrVersionConflict after exhausting retries, got %v", err)
+	}
+}
+
+func TestUpdateTaskFailsFastOnExpectedVersionMismatch(t *testing.T) {
+	testID := primitive.NewObjectID()
+	var updateAttempts int
+	mockRepo := &mockTaskRepository{
+		findByIDFunc: func(ctx context.Context, id primitive.ObjectID) (*models.Task, error) {
+			return &models.Task{ID: testID, Name: "Original Name", Priority: "low", Version: 2}, nil
+		},
+		updateFunc: func(ctx context.Context, id primitive.ObjectID, updated *models.Task, expectedVersion int) error {
+			updateAttempts++
+			return nil
+		},
+	}
+
+	service := NewTaskService(mockRepo)
+
+	staleVersion := 1
+	_, err := service.UpdateTask(context.Background(), testID.Hex(), &models.UpdateTaskRequest{
+		Name:            stringPtr("Updated Name"),
+		ExpectedVersion: &staleVersion,
+	})
+	if code := codeOf(err); code != ErrConflict {
+		t.Errorf("UpdateTask() code = %q, want %q", code, ErrConflict)
+	}
+	if updateAttempts != 0 {
+		t.Errorf("expected no repository write on a version mismatch, got %d", updateAttempts)
+	}
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
 }
+
+// codeOf returns err's ServiceError Code, or "" if err is nil or isn't a
+// *ServiceError.
+func codeOf(err error) Code {
+	var svcErr *ServiceError
+	if errors.As(err, &svcErr) {
+		return svcErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"todo-list-golang/internal/models"
+)
+
+// TaskAuditJobHandler returns the JobHandler for models.JobKindTaskAudit jobs.
+// It simply logs the audit payload: it exists to demonstrate the worker pool
+// end-to-end, not as a substitute for a real audit trail (see the audit
+// package for that).
+func TaskAuditJobHandler() JobHandler {
+	return func(_ context.Context, job *models.Job) error {
+		log.Printf("[task-audit] job %s payload=%v", job.ID.Hex(), job.Payload)
+		return nil
+	}
+}
+
+// JobHandler processes a single acquired job. Returning an error marks the
+// job as failed; returning nil marks it as succeeded.
+type JobHandler func(ctx context.Context, job *models.Job) error
+
+// WorkerPool polls JobService for work and dispatches it to registered
+// handlers, one goroutine per worker slot.
+type WorkerPool struct {
+	jobs     JobService
+	size     int
+	handlers map[string]JobHandler
+
+	mu sync.Mutex
+}
+
+// NewWorkerPool creates a worker pool of the given size. Size is normally
+// sourced from config.Config so operators can tune it per deployment.
+func NewWorkerPool(jobs JobService, size int) *WorkerPool {
+	if size <= 0 {
+		size = 1
+	}
+	return &WorkerPool{
+		jobs:     jobs,
+		size:     size,
+		handlers: make(map[string]JobHandler),
+	}
+}
+
+// RegisterHandler associates a job kind with the function that processes it.
+// A job of an unregistered kind is logged and left running under the lease
+// AcquireJob gave it, neither completed nor failed; once that lease expires
+// the job can be acquired again.
+func (p *WorkerPool) RegisterHandler(kind string, handler JobHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[kind] = handler
+}
+
+// Start launches the worker goroutines. It blocks until ctx is canceled.
+func (p *WorkerPool) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(p.size)
+
+	for i := 0; i < p.size; i++ {
+		workerID := fmt.Sprintf("worker-%d", i+1)
+		go func() {
+			defer wg.Done()
+			p.run(ctx, workerID)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (p *WorkerPool) run(ctx context.Context, workerID string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		job, err := p.jobs.AcquireJob(ctx, workerID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("[worker %s] error acquiring job: %v", workerID, err)
+			continue
+		}
+		if job == nil {
+			continue // long-poll timed out with nothing to do
+		}
+
+		p.dispatch(ctx, workerID, job)
+	}
+}
+
+func (p *WorkerPool) dispatch(ctx context.Context, workerID string, job *models.Job) {
+	p.mu.Lock()
+	handler, ok := p.handlers[job.Kind]
+	p.mu.Unlock()
+
+	if !ok {
+		log.Printf("[worker %s] no handler registered for job kind %q, leaving job %s unfinished until its lease expires", workerID, job.Kind, job.ID.Hex())
+		return
+	}
+
+	if err := handler(ctx, job); err != nil {
+		log.Printf("[worker %s] job %s (%s) failed: %v", workerID, job.ID.Hex(), job.Kind, err)
+		if failErr := p.jobs.FailJob(ctx, job.ID, err); failErr != nil {
+			log.Printf("[worker %s] error recording job failure: %v", workerID, failErr)
+		}
+		return
+	}
+
+	if err := p.jobs.CompleteJob(ctx, job.ID); err != nil {
+		log.Printf("[worker %s] error recording job completion: %v", workerID, err)
+	}
+}
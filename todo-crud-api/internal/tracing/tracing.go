@@ -0,0 +1,48 @@
+// Package tracing wires up an OpenTelemetry tracer provider for the
+// application. When no OTLP endpoint is configured, the global tracer stays
+// a no-op, so middleware.RequestID's spans cost nothing until an exporter is
+// enabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"todo-list-golang/internal/config"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// Init configures the global tracer provider from cfg. If cfg.ExporterOTLPEndpoint
+// is empty, it leaves the global provider untouched (OpenTelemetry's default
+// is a no-op) and the returned shutdown func is a
+// no-op. Otherwise it dials the OTLP gRPC exporter and returns a shutdown
+// func that flushes and closes it; callers should defer the shutdown func.
+func Init(ctx context.Context, cfg config.OTelConfig) (func(context.Context) error, error) {
+	if cfg.ExporterOTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(cfg.ExporterOTLPEndpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("error creating OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(),
+		resource.NewSchemaless(semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("error building OTel resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}